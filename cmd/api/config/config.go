@@ -22,6 +22,18 @@ type Config struct {
 	JWTExpiryTime time.Duration `json:"jwt_expiry_time"`
 	LimitDefault  int           `json:"limit_default"`
 	LimitMax      int           `json:"limit_max"`
+	BlobStore     string        `json:"blob_store"`
+	BlobDir       string        `json:"blob_dir"`
+	S3Endpoint    string        `json:"s3_endpoint"`
+	S3Region      string        `json:"s3_region"`
+	S3Bucket      string        `json:"s3_bucket"`
+	S3AccessKey   string        `json:"s3_access_key"`
+	S3SecretKey   string        `json:"s3_secret_key"`
+	BrandName     string        `json:"brand_name"`
+	BrandLogo     string        `json:"brand_logo"`
+	QuoteTerms    string        `json:"quote_terms"`
+	WebhookURL    string        `json:"webhook_url"`
+	WebhookSecret string        `json:"webhook_secret"`
 }
 
 // ParseConfigFile parses the API configuration file.
@@ -1,16 +1,21 @@
 package context
 
-import "estimator/services"
+import (
+	"estimator/cmd/api/config"
+	"estimator/services"
+)
 
 // Context defines the API context, which will contain the configuration,
 // services, logger, and anything else needed across HTTP handlers.
 type Context struct {
+	Config   *config.Config
 	Services *services.Services
 }
 
 // New creates a new API context.
-func New(s *services.Services) *Context {
+func New(cfg *config.Config, s *services.Services) *Context {
 	return &Context{
+		Config:   cfg,
 		Services: s,
 	}
 }
@@ -11,7 +11,10 @@ import (
 	apictx "estimator/cmd/api/context"
 	v1 "estimator/cmd/api/v1"
 	"estimator/services"
+	"estimator/services/webhook"
+	fsblob "estimator/storage/filesystem/blob"
 	"estimator/storage/mysql"
+	s3blob "estimator/storage/s3/blob"
 
 	"github.com/beeker1121/httprouter"
 	_ "github.com/go-sql-driver/mysql"
@@ -33,6 +36,9 @@ func main() {
 	cfg.APIHost = os.Getenv("API_HOST")
 	cfg.APIPort = os.Getenv("API_PORT")
 	cfg.JWTSecret = os.Getenv("JWT_SECRET")
+	cfg.S3AccessKey = os.Getenv("S3_ACCESS_KEY")
+	cfg.S3SecretKey = os.Getenv("S3_SECRET_KEY")
+	cfg.WebhookSecret = os.Getenv("WEBHOOK_SECRET")
 
 	// TODO: Add logger.
 
@@ -51,14 +57,43 @@ func main() {
 	// Create a new MySQL storage implementation.
 	store := mysql.New(db)
 
+	// Create the blob store.
+	switch cfg.BlobStore {
+	case "s3":
+		store.Blob = s3blob.New(s3blob.Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		store.Blob = fsblob.New(cfg.BlobDir)
+	}
+
 	// Create new services.
 	serv := services.New(store)
 
+	// Post quote events to the webhook.
+	if cfg.WebhookURL != "" {
+		serv.Quote.Subscribe(webhook.New(cfg.WebhookURL, cfg.WebhookSecret).QuoteEvent)
+	}
+
+	// Expire open quotes past their expiry date.
+	go func() {
+		for range time.Tick(time.Minute) {
+			if err := serv.Quote.Expire(); err != nil {
+				// TODO: Use logger.
+				fmt.Printf("error expiring quotes: %v\n", err)
+			}
+		}
+	}()
+
 	// Create a new router.
 	router := httprouter.New()
 
 	// Create a new API context.
-	ac := apictx.New(serv)
+	ac := apictx.New(cfg, serv)
 
 	// Create a new v1 API.
 	v1.New(ac, router)
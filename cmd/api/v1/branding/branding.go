@@ -0,0 +1,26 @@
+package branding
+
+import (
+	apictx "estimator/cmd/api/context"
+	"estimator/render/pdf"
+)
+
+// Get gets the branding of rendered documents from the API configuration,
+// loading the logo asset when set.
+func Get(ac *apictx.Context) (*pdf.Branding, error) {
+	b := &pdf.Branding{
+		Name:  ac.Config.BrandName,
+		Terms: ac.Config.QuoteTerms,
+	}
+
+	// Get the logo.
+	if ac.Config.BrandLogo != "" {
+		logo, err := ac.Services.Asset.Image(ac.Config.BrandLogo)
+		if err != nil {
+			return nil, err
+		}
+		b.Logo = logo
+	}
+
+	return b, nil
+}
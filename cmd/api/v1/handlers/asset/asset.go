@@ -0,0 +1,105 @@
+package asset
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	apictx "estimator/cmd/api/context"
+	"estimator/cmd/api/response"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Asset defines the asset response.
+type Asset struct {
+	ID          string `json:"id"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+// New creates a new asset handler.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.POST("/api/v1/asset", HandleUpload(ac))
+	router.GET("/api/v1/asset/:id", HandleGet(ac))
+}
+
+// HandleUpload is the HTTP handler function for uploading an image asset.
+// The image is sent as a multipart part named "file".
+func HandleUpload(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the file part.
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			w.Write([]byte("error reading multipart request body"))
+			return
+		}
+		defer file.Close()
+
+		// Upload the asset.
+		a, err := ac.Services.Asset.Upload(file)
+		if err != nil {
+			w.Write([]byte("error uploading asset"))
+			return
+		}
+
+		// Map to API asset response.
+		res := &Asset{
+			ID:          a.ID,
+			URL:         a.URL,
+			ContentType: a.ContentType,
+			Size:        a.Size,
+			Width:       a.Width,
+			Height:      a.Height,
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGet is the HTTP handler function for getting an asset's data. Assets
+// are content-addressed, so they never change and can be cached forever.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the asset ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Handle conditional requests.
+		etag := `"` + id + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		// Get the asset.
+		a, rc, err := ac.Services.Asset.GetByID(id)
+		// TODO: Implement else if for ErrBlobNotFound.
+		if err != nil {
+			w.Write([]byte("error getting asset"))
+			return
+		}
+		defer rc.Close()
+
+		// Set headers.
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Type", a.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("ETag", etag)
+
+		// Stream the data.
+		if _, err := io.Copy(w, rc); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
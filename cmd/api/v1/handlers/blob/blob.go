@@ -0,0 +1,150 @@
+package blob
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apictx "estimator/cmd/api/context"
+	"estimator/cmd/api/response"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Blob defines the blob response.
+type Blob struct {
+	ID          string    `json:"id"`
+	ContentType string    `json:"content_type"`
+	Filename    string    `json:"filename"`
+	Size        int64     `json:"size"`
+	Created     time.Time `json:"created"`
+}
+
+// New creates a new blob handler.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.POST("/api/v1/form/:id/upload/:name", HandleUpload(ac))
+	router.GET("/api/v1/blob/:id", HandleGet(ac))
+}
+
+// HandleUpload is the HTTP handler function for uploading files to a file
+// upload module. Files are sent as multipart parts named "file".
+func HandleUpload(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the form ID and module name.
+		id := httprouter.GetParam(r, "id")
+		name := httprouter.GetParam(r, "name")
+
+		// Get the file upload module.
+		m, err := ac.Services.Blob.GetFileUploadModule(id, name)
+		if err != nil {
+			w.Write([]byte("error getting file upload module"))
+			return
+		}
+
+		// Stream the multipart request body.
+		mr, err := r.MultipartReader()
+		if err != nil {
+			w.Write([]byte("error reading multipart request body"))
+			return
+		}
+
+		// Delete the files already stored when the upload fails part way.
+		res := []Blob{}
+		cleanup := func() {
+			for _, v := range res {
+				if err := ac.Services.Blob.Delete(v.ID); err != nil {
+					// TODO: Use logger.
+					fmt.Printf("error in handler: %v\n", err)
+				}
+			}
+		}
+
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				cleanup()
+				w.Write([]byte("error reading multipart request body"))
+				return
+			}
+
+			// Skip anything that is not a file.
+			if part.FormName() != "file" {
+				continue
+			}
+
+			// Check count.
+			if len(res) >= m.Properties.MaxCount {
+				cleanup()
+				w.Write([]byte("error uploading files, too many files"))
+				return
+			}
+
+			// Upload the file.
+			b, err := ac.Services.Blob.Upload(m, part.FileName(), part)
+			if err != nil {
+				cleanup()
+				w.Write([]byte("error uploading file"))
+				return
+			}
+
+			res = append(res, Blob{
+				ID:          b.ID,
+				ContentType: b.ContentType,
+				Filename:    b.Filename,
+				Size:        b.Size,
+				Created:     b.Created,
+			})
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGet is the HTTP handler function for getting a blob's data.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the blob ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Get the blob.
+		b, rc, err := ac.Services.Blob.GetByID(id)
+		// TODO: Implement else if for ErrBlobNotFound.
+		if err != nil {
+			w.Write([]byte("error getting blob"))
+			return
+		}
+		defer rc.Close()
+
+		// Set headers. Only images are displayed inline.
+		disposition := "attachment"
+		if strings.HasPrefix(b.ContentType, "image/") {
+			disposition = "inline"
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Type", b.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(b.Size, 10))
+		params := map[string]string{}
+		if b.Filename != "" {
+			params["filename"] = b.Filename
+		}
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, params))
+
+		// Stream the data.
+		if _, err := io.Copy(w, rc); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
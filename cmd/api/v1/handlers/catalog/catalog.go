@@ -0,0 +1,206 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	apictx "estimator/cmd/api/context"
+	"estimator/cmd/api/response"
+	"estimator/types"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Item defines the catalog item request/response.
+type Item struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	SKU         string        `json:"sku"`
+	Description string        `json:"description"`
+	UnitPrice   types.Decimal `json:"unit_price"`
+	MinPrice    types.Decimal `json:"min_price"`
+	MaxPrice    types.Decimal `json:"max_price"`
+	Unit        string        `json:"unit"`
+	Image       string        `json:"image"`
+	ImageURL    string        `json:"image_url"`
+	MinQuantity int           `json:"min_quantity"`
+	MaxQuantity int           `json:"max_quantity"`
+}
+
+// New creates a new catalog handler.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.POST("/api/v1/catalog", HandleCreate(ac))
+	router.GET("/api/v1/catalog", HandleGet(ac))
+	router.GET("/api/v1/catalog/:id", HandleGetByID(ac))
+	router.POST("/api/v1/catalog/:id", HandleUpdate(ac))
+	router.DELETE("/api/v1/catalog/:id", HandleDelete(ac))
+}
+
+// toType maps a catalog item request to a catalog item.
+func (i *Item) toType() *types.CatalogItem {
+	return &types.CatalogItem{
+		Name:        i.Name,
+		SKU:         i.SKU,
+		Description: i.Description,
+		UnitPrice:   i.UnitPrice,
+		MinPrice:    i.MinPrice,
+		MaxPrice:    i.MaxPrice,
+		Unit:        i.Unit,
+		Image:       i.Image,
+		MinQuantity: i.MinQuantity,
+		MaxQuantity: i.MaxQuantity,
+	}
+}
+
+// fromType maps a catalog item to a catalog item response.
+func fromType(ci *types.CatalogItem) Item {
+	return Item{
+		ID:          ci.ID,
+		Name:        ci.Name,
+		SKU:         ci.SKU,
+		Description: ci.Description,
+		UnitPrice:   ci.UnitPrice,
+		MinPrice:    ci.MinPrice,
+		MaxPrice:    ci.MaxPrice,
+		Unit:        ci.Unit,
+		Image:       ci.Image,
+		ImageURL:    types.AssetURL(ci.Image),
+		MinQuantity: ci.MinQuantity,
+		MaxQuantity: ci.MaxQuantity,
+	}
+}
+
+// HandleCreate is the HTTP handler function for creating a catalog item.
+func HandleCreate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the request body.
+		var i Item
+		if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Create a new services catalog item.
+		ci, err := ac.Services.Catalog.Create(i.toType())
+		// TODO: Implement else if for ErrSKUExists.
+		if err != nil {
+			w.Write([]byte("error creating catalog item"))
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, fromType(ci)); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGet is the HTTP handler function for getting a page of catalog
+// items.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the limit and offset.
+		limit := ac.Config.LimitDefault
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+			limit = v
+		}
+		if limit > ac.Config.LimitMax {
+			limit = ac.Config.LimitMax
+		}
+		offset := 0
+		if v, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && v > 0 {
+			offset = v
+		}
+
+		// Get the catalog items.
+		cis, err := ac.Services.Catalog.Get(limit, offset)
+		if err != nil {
+			w.Write([]byte("error getting catalog items"))
+			return
+		}
+
+		// Map to API catalog item responses.
+		res := []Item{}
+		for _, ci := range cis {
+			res = append(res, fromType(ci))
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGetByID is the HTTP handler function for getting a catalog item.
+func HandleGetByID(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the catalog item ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Get the catalog item.
+		ci, err := ac.Services.Catalog.GetByID(id)
+		// TODO: Implement else if for ErrItemNotFound.
+		if err != nil {
+			w.Write([]byte("error getting catalog item"))
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, fromType(ci)); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleUpdate is the HTTP handler function for updating a catalog item.
+func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the request body.
+		var i Item
+		if err := json.NewDecoder(r.Body).Decode(&i); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Get the catalog item ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Update the catalog item.
+		ci, err := ac.Services.Catalog.UpdateByID(id, i.toType())
+		// TODO: Implement else if for ErrItemNotFound and ErrSKUExists.
+		if err != nil {
+			w.Write([]byte("error updating catalog item"))
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, fromType(ci)); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleDelete is the HTTP handler function for deleting a catalog item.
+func HandleDelete(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the catalog item ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Delete the catalog item.
+		// TODO: Implement else if for ErrItemNotFound.
+		if err := ac.Services.Catalog.DeleteByID(id); err != nil {
+			w.Write([]byte("error deleting catalog item"))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,197 @@
+package estimate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	apictx "estimator/cmd/api/context"
+	"estimator/cmd/api/response"
+	"estimator/storage/coupon"
+	"estimator/storage/exchangerate"
+	"estimator/types"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Request defines the estimate request. Locale optionally overrides the
+// form locale the amounts are formatted for, and Currency optionally
+// converts the estimate to one of the form display currencies.
+type Request struct {
+	Answers  map[string]interface{} `json:"answers"`
+	Coupon   string                 `json:"coupon"`
+	Locale   string                 `json:"locale"`
+	Currency string                 `json:"currency"`
+}
+
+// Estimate defines the estimate response. Total is the expected total.
+// Amounts are numbers in the major unit of the currency, such as 1234.50,
+// and Formatted holds them formatted for the locale. Conversion is set when
+// the estimate was converted from the form currency.
+type Estimate struct {
+	FormID     string      `json:"form_id"`
+	Lines      []Line      `json:"lines"`
+	Coupon     string      `json:"coupon"`
+	Currency   string      `json:"currency"`
+	Locale     string      `json:"locale"`
+	Subtotal   types.Money `json:"subtotal"`
+	Tax        types.Money `json:"tax"`
+	Total      types.Money `json:"total"`
+	Low        types.Money `json:"low"`
+	High       types.Money `json:"high"`
+	Expected   types.Money `json:"expected"`
+	Formatted  Formatted   `json:"formatted"`
+	Conversion *Conversion `json:"conversion"`
+}
+
+// Conversion defines the exchange rate an estimate was converted with, and
+// when the rate was last updated.
+type Conversion struct {
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Rate        types.Decimal `json:"rate"`
+	RateUpdated time.Time     `json:"rate_updated"`
+}
+
+// Formatted defines the estimate totals formatted for the locale. Range is
+// the low to high range, such as "$1,200.00 – $1,600.00".
+type Formatted struct {
+	Subtotal string `json:"subtotal"`
+	Tax      string `json:"tax"`
+	Total    string `json:"total"`
+	Low      string `json:"low"`
+	High     string `json:"high"`
+	Range    string `json:"range"`
+}
+
+// Line defines an estimate line response.
+type Line struct {
+	Kind        string        `json:"kind"`
+	Module      string        `json:"module"`
+	Description string        `json:"description"`
+	SKU         string        `json:"sku"`
+	Quantity    float64       `json:"quantity"`
+	Unit        string        `json:"unit"`
+	UnitPrice   types.Decimal `json:"unit_price"`
+	Amount      types.Money   `json:"amount"`
+	Low         types.Money   `json:"low"`
+	High        types.Money   `json:"high"`
+	Included    bool          `json:"included"`
+	Formatted   LineFormatted `json:"formatted"`
+}
+
+// LineFormatted defines the estimate line amounts formatted for the
+// locale.
+type LineFormatted struct {
+	Amount string `json:"amount"`
+	Low    string `json:"low"`
+	High   string `json:"high"`
+}
+
+// New creates a new estimate handler.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.POST("/api/v1/form/:id/estimate", HandleEstimate(ac))
+}
+
+// FromType maps an estimate to an estimate response, formatting the amounts
+// for the given locale.
+func FromType(se *types.Estimate, locale string) *Estimate {
+	res := &Estimate{
+		FormID:   se.FormID,
+		Lines:    []Line{},
+		Coupon:   se.Coupon,
+		Currency: se.Currency,
+		Locale:   locale,
+		Subtotal: se.Subtotal,
+		Tax:      se.Tax,
+		Total:    se.Total,
+		Low:      se.Low,
+		High:     se.High,
+		Expected: se.Total,
+		Formatted: Formatted{
+			Subtotal: se.Subtotal.Format(locale),
+			Tax:      se.Tax.Format(locale),
+			Total:    se.Total.Format(locale),
+			Low:      se.Low.Format(locale),
+			High:     se.High.Format(locale),
+			Range:    se.Low.Format(locale) + " – " + se.High.Format(locale),
+		},
+	}
+	if se.Conversion != nil {
+		res.Conversion = &Conversion{
+			From:        se.Conversion.From,
+			To:          se.Conversion.To,
+			Rate:        se.Conversion.Rate,
+			RateUpdated: se.Conversion.Updated,
+		}
+	}
+	for _, v := range se.Lines {
+		res.Lines = append(res.Lines, Line{
+			Kind:        v.Kind,
+			Module:      v.Module,
+			Description: v.Description,
+			SKU:         v.SKU,
+			Quantity:    v.Quantity,
+			Unit:        v.Unit,
+			UnitPrice:   v.UnitPrice,
+			Amount:      v.Amount,
+			Low:         v.Low,
+			High:        v.High,
+			Included:    v.Included,
+			Formatted: LineFormatted{
+				Amount: v.Amount.Format(locale),
+				Low:    v.Low.Format(locale),
+				High:   v.High.Format(locale),
+			},
+		})
+	}
+
+	return res
+}
+
+// HandleEstimate is the HTTP handler function for estimating a form.
+func HandleEstimate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the request body.
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Estimate the form.
+		se, err := ac.Services.Estimate.Estimate(id, req.Answers, req.Coupon, req.Currency)
+		// TODO: Implement else if for ErrFormNotFound.
+		switch {
+		case err == types.ErrCouponNotFound, err == types.ErrCouponNotValid, err == coupon.ErrUsageLimitReached:
+			w.Write([]byte("error applying coupon"))
+			return
+		case err == types.ErrCurrencyNotAllowed, err == exchangerate.ErrRateNotFound:
+			w.Write([]byte("error converting currency"))
+			return
+		case err != nil:
+			w.Write([]byte("error estimating form"))
+			return
+		}
+
+		// Get the locale to format for.
+		locale := se.Locale
+		if req.Locale != "" && types.ValidLocale(req.Locale) {
+			locale = req.Locale
+		}
+
+		// Map to API estimate response.
+		res := FromType(se, locale)
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package exchangerate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	apictx "estimator/cmd/api/context"
+	"estimator/cmd/api/response"
+	"estimator/types"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Rate defines the exchange rate request/response. The rate converts an
+// amount in the base currency to the quote currency.
+type Rate struct {
+	Base    string        `json:"base"`
+	Quote   string        `json:"quote"`
+	Rate    types.Decimal `json:"rate"`
+	Updated time.Time     `json:"updated"`
+}
+
+// New creates a new exchange rate handler.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.GET("/api/v1/exchange-rates", HandleGet(ac))
+	router.POST("/api/v1/exchange-rates", HandleSet(ac))
+	router.POST("/api/v1/exchange-rates/import", HandleImport(ac))
+}
+
+// fromType maps an exchange rate to an exchange rate response.
+func fromType(er *types.ExchangeRate) Rate {
+	return Rate{
+		Base:    er.Base,
+		Quote:   er.Quote,
+		Rate:    er.Rate,
+		Updated: er.Updated,
+	}
+}
+
+// HandleGet is the HTTP handler function for getting all exchange rates.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the exchange rates.
+		ers, err := ac.Services.ExchangeRate.Get()
+		if err != nil {
+			w.Write([]byte("error getting exchange rates"))
+			return
+		}
+
+		// Map to API exchange rate responses.
+		res := []Rate{}
+		for _, er := range ers {
+			res = append(res, fromType(er))
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleSet is the HTTP handler function for setting an exchange rate.
+func HandleSet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the request body.
+		var req Rate
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Set the exchange rate.
+		er, err := ac.Services.ExchangeRate.Set(&types.ExchangeRate{
+			Base:  req.Base,
+			Quote: req.Quote,
+			Rate:  req.Rate,
+		})
+		if err != nil {
+			w.Write([]byte("error setting exchange rate"))
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, fromType(er)); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleImport is the HTTP handler function for importing exchange rates.
+// The request body is CSV with a base, quote and rate per line, such as
+// "USD,CAD,1.36".
+func HandleImport(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Import the exchange rates.
+		ers, err := ac.Services.ExchangeRate.Import(r.Body)
+		if err != nil {
+			w.Write([]byte("error importing exchange rates"))
+			return
+		}
+
+		// Map to API exchange rate responses.
+		res := []Rate{}
+		for _, er := range ers {
+			res = append(res, fromType(er))
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
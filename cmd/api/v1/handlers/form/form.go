@@ -2,20 +2,98 @@ package form
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	apictx "estimator/cmd/api/context"
 	"estimator/cmd/api/response"
+	formsvc "estimator/services/form"
+	"estimator/storage/form"
 	"estimator/types"
+	"estimator/utils/jsonpatch"
 
 	"github.com/beeker1121/httprouter"
 )
 
-// Form defines the form request/response.
+// Form defines the form request/response. Revision is the number of the
+// revision the modules are from, and UnpublishedChanges is set when the
+// draft has changed since it was published. Version is the version, which
+// is also sent as the ETag header. These are ignored in requests, where the
+// version is taken from the If-Match header.
 type Form struct {
-	ID      string        `json:"id"`
-	Modules []interface{} `json:"modules"`
+	ID                 string        `json:"id"`
+	Modules            []interface{} `json:"modules"`
+	Pricing            interface{}   `json:"pricing"`
+	Revision           int           `json:"revision"`
+	PublishedRevision  int           `json:"published_revision"`
+	UnpublishedChanges bool          `json:"unpublished_changes"`
+	Version            int           `json:"version"`
+}
+
+// Revision defines the form revision response. The form is left out of
+// revision lists.
+type Revision struct {
+	Number  int       `json:"number"`
+	Author  string    `json:"author"`
+	Created time.Time `json:"created"`
+	Form    *Form     `json:"form,omitempty"`
+}
+
+// Diff defines the form revision diff response.
+type Diff struct {
+	From    int            `json:"from"`
+	To      int            `json:"to"`
+	Modules []ModuleChange `json:"modules"`
+	Pricing bool           `json:"pricing"`
+}
+
+// ModuleChange defines a module change of a diff response.
+type ModuleChange struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Change string   `json:"change"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ModuleInsert defines the module insert request. The module is inserted
+// among the children of the parent module, or the top level modules when
+// the parent ID is empty, and appended when the position is left out.
+type ModuleInsert struct {
+	ParentID string      `json:"parent_id"`
+	Position *int        `json:"position"`
+	Module   interface{} `json:"module"`
+}
+
+// ModuleOrder defines the module reorder request, with the IDs of the
+// children of the parent module, or the top level modules when the parent
+// ID is empty, in their new order.
+type ModuleOrder struct {
+	ParentID string   `json:"parent_id"`
+	Order    []string `json:"order"`
+}
+
+// Pages defines the form pages response.
+type Pages struct {
+	FormID string `json:"form_id"`
+	Total  int    `json:"total"`
+	Pages  []Page `json:"pages"`
+}
+
+// Page defines a form page response.
+type Page struct {
+	Number    int           `json:"number"`
+	Title     string        `json:"title"`
+	NextLabel string        `json:"next_label"`
+	BackLabel string        `json:"back_label"`
+	Progress  int           `json:"progress"`
+	Modules   []interface{} `json:"modules"`
 }
 
 // New creates a new form handler.
@@ -24,14 +102,57 @@ func New(ac *apictx.Context, router *httprouter.Router) {
 	router.POST("/api/v1/form", HandleCreate(ac))
 	router.GET("/api/v1/form/:id", HandleGet(ac))
 	router.POST("/api/v1/form/:id", HandleUpdate(ac))
+	router.PATCH("/api/v1/form/:id", HandlePatch(ac))
+	router.GET("/api/v1/form/:id/pages", HandleGetPages(ac))
+	router.GET("/api/v1/form/:id/draft", HandleGetDraft(ac))
+	router.POST("/api/v1/form/:id/publish", HandlePublish(ac))
+	router.GET("/api/v1/form/:id/revisions", HandleGetRevisions(ac))
+	router.GET("/api/v1/form/:id/revisions/:number", HandleGetRevision(ac))
+	router.POST("/api/v1/form/:id/revisions/:number/revert", HandleRevert(ac))
+	router.GET("/api/v1/form/:id/diff", HandleDiff(ac))
+	router.POST("/api/v1/form/:id/modules", HandleInsertModule(ac))
+	router.POST("/api/v1/form/:id/modules/reorder", HandleReorderModules(ac))
+	router.GET("/api/v1/form/:id/modules/:moduleID", HandleGetModule(ac))
+	router.PUT("/api/v1/form/:id/modules/:moduleID", HandleUpdateModule(ac))
+	router.DELETE("/api/v1/form/:id/modules/:moduleID", HandleDeleteModule(ac))
+}
+
+// etag returns the ETag header of the given form version.
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ifMatch gets the form version from the If-Match header of the request,
+// responding with an error if it is missing or invalid.
+func ifMatch(w http.ResponseWriter, r *http.Request) (int, bool) {
+	// Check the header is set.
+	h := strings.TrimSpace(r.Header.Get("If-Match"))
+	if h == "" {
+		w.WriteHeader(http.StatusPreconditionRequired)
+		w.Write([]byte("error updating form, If-Match header is required"))
+		return 0, false
+	}
+
+	// Parse the version.
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(h, "W/"), `"`))
+	if err != nil {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		w.Write([]byte("error updating form, invalid If-Match header"))
+		return 0, false
+	}
+
+	return version, true
 }
 
 // HandleCreate is the HTTP handler function for creating a form.
 func HandleCreate(ac *apictx.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Parse the request body.
+		// Parse the request body, keeping numbers as written so prices are
+		// exact.
 		var f Form
-		if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&f); err != nil {
 			w.Write([]byte("error decoding request body"))
 			return
 		}
@@ -43,9 +164,17 @@ func HandleCreate(ac *apictx.Context) http.HandlerFunc {
 			return
 		}
 
+		// Map pricing interface to pricing settings.
+		pricing, err := ac.Services.Form.InterfaceToPricing(f.Pricing)
+		if err != nil {
+			w.Write([]byte("error converting interface to pricing"))
+			return
+		}
+
 		// Create a new services form.
 		sf, err := ac.Services.Form.Create(&types.Form{
 			Modules: modules,
+			Pricing: pricing,
 		})
 		if err != nil {
 			w.Write([]byte("error creating form"))
@@ -54,7 +183,12 @@ func HandleCreate(ac *apictx.Context) http.HandlerFunc {
 
 		// Create a new response form.
 		res := Form{
-			ID: sf.ID,
+			ID:                 sf.ID,
+			Pricing:            sf.Pricing,
+			Revision:           sf.Revision,
+			Version:            sf.Version,
+			PublishedRevision:  sf.PublishedRevision,
+			UnpublishedChanges: sf.UnpublishedChanges,
 		}
 
 		// Get JSON for modules.
@@ -69,6 +203,7 @@ func HandleCreate(ac *apictx.Context) http.HandlerFunc {
 		}
 
 		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
 		if err := response.JSON(w, true, res); err != nil {
 			// TODO: Use logger.
 			fmt.Printf("error in handler: %v\n", err)
@@ -76,14 +211,15 @@ func HandleCreate(ac *apictx.Context) http.HandlerFunc {
 	}
 }
 
-// HandleGet is the HTTP handler function for getting a form.
+// HandleGet is the HTTP handler function for getting the published revision
+// of a form, as customers see it.
 func HandleGet(ac *apictx.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get the form ID.
 		id := httprouter.GetParam(r, "id")
 
-		// Get the form.
-		sf, err := ac.Services.Form.GetByID(id)
+		// Get the published form.
+		sf, err := ac.Services.Form.GetPublished(id)
 		// TODO: Implement else if for ErrFormNotFound.
 		if err != nil {
 			// TODO: Create response package to handle sending back JSON and
@@ -92,16 +228,29 @@ func HandleGet(ac *apictx.Context) http.HandlerFunc {
 			return
 		}
 
+		// Prefill modules from the query parameters.
+		ac.Services.Form.Prefill(sf, r.URL.Query(), r.Referer())
+
 		// Map to API form response.
 		f := &Form{
-			ID:      sf.ID,
-			Modules: []interface{}{},
+			ID:                 sf.ID,
+			Modules:            []interface{}{},
+			Revision:           sf.Revision,
+			Version:            sf.Version,
+			PublishedRevision:  sf.PublishedRevision,
+			UnpublishedChanges: sf.UnpublishedChanges,
 		}
+
+		// Leave out the coupons, so customers can't see the codes.
+		pricing := *sf.Pricing
+		pricing.Coupons = nil
+		f.Pricing = pricing
 		for _, v := range sf.Modules {
 			f.Modules = append(f.Modules, v)
 		}
 
 		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
 		if err := response.JSON(w, true, f); err != nil {
 			// TODO: Use logger.
 			fmt.Printf("error in handler: %v\n", err)
@@ -109,12 +258,71 @@ func HandleGet(ac *apictx.Context) http.HandlerFunc {
 	}
 }
 
-// HandleUpdate is the HTTP handler function for creating a form.
+// HandleGetPages is the HTTP handler function for getting the published
+// revision of a form split into pages.
+func HandleGetPages(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Get the published form.
+		sf, err := ac.Services.Form.GetPublished(id)
+		// TODO: Implement else if for ErrFormNotFound.
+		if err != nil {
+			w.Write([]byte("error getting form"))
+			return
+		}
+
+		// Prefill modules from the query parameters.
+		ac.Services.Form.Prefill(sf, r.URL.Query(), r.Referer())
+
+		// Map to API pages response.
+		pages := sf.Pages()
+		res := &Pages{
+			FormID: sf.ID,
+			Total:  len(pages),
+			Pages:  []Page{},
+		}
+		for _, v := range pages {
+			page := Page{
+				Number:    v.Number,
+				Title:     v.Title,
+				NextLabel: v.NextLabel,
+				BackLabel: v.BackLabel,
+				Progress:  v.Progress,
+				Modules:   []interface{}{},
+			}
+			for _, module := range v.Modules {
+				page.Modules = append(page.Modules, module)
+			}
+			res.Pages = append(res.Pages, page)
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleUpdate is the HTTP handler function for updating the working draft
+// of a form. The If-Match header must hold the ETag of the version being
+// updated.
 func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Parse the request body.
+		// Get the version being updated.
+		version, ok := ifMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Parse the request body, keeping numbers as written so prices are
+		// exact.
 		var f Form
-		if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&f); err != nil {
 			w.Write([]byte("error decoding request body"))
 			return
 		}
@@ -131,13 +339,27 @@ func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
 			return
 		}
 
+		// Map pricing interface to pricing settings.
+		pricing, err := ac.Services.Form.InterfaceToPricing(f.Pricing)
+		if err != nil {
+			w.Write([]byte("error converting interface to pricing"))
+			return
+		}
+
 		// Update the form.
 		sf, err := ac.Services.Form.UpdateByIDAndMemberID(id, "", &types.Form{
 			Modules: modules,
+			Pricing: pricing,
+			Version: version,
 		})
 		// TODO: Implement param error type check first.
 		// TODO: Implement else if for ErrFormNotFound.
-		if err != nil {
+		switch {
+		case err == form.ErrVersionConflict:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("error updating form, the form was changed by someone else"))
+			return
+		case err != nil:
 			// TODO: Create response package to handle sending back JSON and
 			//       errors.
 			w.Write([]byte("error getting form"))
@@ -146,7 +368,12 @@ func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
 
 		// Create a new response form.
 		res := Form{
-			ID: sf.ID,
+			ID:                 sf.ID,
+			Pricing:            sf.Pricing,
+			Revision:           sf.Revision,
+			Version:            sf.Version,
+			PublishedRevision:  sf.PublishedRevision,
+			UnpublishedChanges: sf.UnpublishedChanges,
 		}
 
 		// Get JSON for modules.
@@ -161,6 +388,648 @@ func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
 		}
 
 		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandlePatch is the HTTP handler function for partially updating the
+// working draft of a form. The body is a JSON Patch document when the
+// Content-Type is application/json-patch+json, or a JSON Merge Patch
+// document when it is application/merge-patch+json, applied to an object
+// with the draft "modules" and "pricing". The If-Match header must hold the
+// ETag of the version being patched.
+func HandlePatch(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the patch format.
+		var format string
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch mediaType {
+		case "application/json-patch+json":
+			format = formsvc.PatchJSON
+		case "application/merge-patch+json":
+			format = formsvc.PatchMerge
+		default:
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			w.Write([]byte("error patching form, unsupported patch content type"))
+			return
+		}
+
+		// Get the version being patched.
+		version, ok := ifMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Read the request body.
+		patch, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.Write([]byte("error reading request body"))
+			return
+		}
+
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// TODO: Get this member from the request context.
+
+		// Patch the form.
+		sf, err := ac.Services.Form.Patch(id, "", version, format, patch)
+		// TODO: Implement else if for ErrFormNotFound.
+		switch {
+		case err == form.ErrVersionConflict:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("error patching form, the form was changed by someone else"))
+			return
+		case errors.Is(err, jsonpatch.ErrTestFailed):
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("error patching form, patch test failed"))
+			return
+		case err != nil:
+			w.Write([]byte("error patching form"))
+			return
+		}
+
+		// Map to API form response.
+		res := &Form{
+			ID:                 sf.ID,
+			Modules:            []interface{}{},
+			Pricing:            sf.Pricing,
+			Revision:           sf.Revision,
+			Version:            sf.Version,
+			PublishedRevision:  sf.PublishedRevision,
+			UnpublishedChanges: sf.UnpublishedChanges,
+		}
+		for _, v := range sf.Modules {
+			res.Modules = append(res.Modules, v)
+		}
+
+		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGetDraft is the HTTP handler function for getting the working draft
+// of a form, for editing it.
+func HandleGetDraft(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Get the draft.
+		sf, err := ac.Services.Form.GetByID(id)
+		// TODO: Implement else if for ErrFormNotFound.
+		if err != nil {
+			w.Write([]byte("error getting form"))
+			return
+		}
+
+		// Map to API form response.
+		res := &Form{
+			ID:                 sf.ID,
+			Modules:            []interface{}{},
+			Pricing:            sf.Pricing,
+			Revision:           sf.Revision,
+			Version:            sf.Version,
+			PublishedRevision:  sf.PublishedRevision,
+			UnpublishedChanges: sf.UnpublishedChanges,
+		}
+		for _, v := range sf.Modules {
+			res.Modules = append(res.Modules, v)
+		}
+
+		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandlePublish is the HTTP handler function for publishing the working
+// draft of a form, so customers see it. The If-Match header must hold the
+// ETag of the version being published.
+func HandlePublish(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the version being published.
+		version, ok := ifMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Publish the form.
+		sf, err := ac.Services.Form.Publish(id, version)
+		// TODO: Implement else if for ErrFormNotFound.
+		switch {
+		case err == form.ErrVersionConflict:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("error publishing form, the form was changed by someone else"))
+			return
+		case err != nil:
+			w.Write([]byte("error publishing form"))
+			return
+		}
+
+		// Map to API form response.
+		res := &Form{
+			ID:                 sf.ID,
+			Modules:            []interface{}{},
+			Pricing:            sf.Pricing,
+			Revision:           sf.Revision,
+			Version:            sf.Version,
+			PublishedRevision:  sf.PublishedRevision,
+			UnpublishedChanges: sf.UnpublishedChanges,
+		}
+		for _, v := range sf.Modules {
+			res.Modules = append(res.Modules, v)
+		}
+
+		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGetRevisions is the HTTP handler function for getting a page of the
+// revisions of a form, newest first.
+func HandleGetRevisions(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Get the limit and offset.
+		limit := ac.Config.LimitDefault
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+			limit = v
+		}
+		if limit > ac.Config.LimitMax {
+			limit = ac.Config.LimitMax
+		}
+		offset := 0
+		if v, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && v > 0 {
+			offset = v
+		}
+
+		// Get the revisions.
+		srs, err := ac.Services.Form.GetRevisions(id, limit, offset)
+		// TODO: Implement else if for ErrFormNotFound.
+		if err != nil {
+			w.Write([]byte("error getting form revisions"))
+			return
+		}
+
+		// Map to API revision responses.
+		res := []*Revision{}
+		for _, sr := range srs {
+			res = append(res, &Revision{
+				Number:  sr.Number,
+				Author:  sr.Author,
+				Created: sr.Created,
+			})
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGetRevision is the HTTP handler function for getting a revision of
+// a form.
+func HandleGetRevision(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the form ID and revision number.
+		id := httprouter.GetParam(r, "id")
+		number, err := strconv.Atoi(httprouter.GetParam(r, "number"))
+		if err != nil {
+			w.Write([]byte("error parsing revision number"))
+			return
+		}
+
+		// Get the revision.
+		sr, err := ac.Services.Form.GetRevision(id, number)
+		// TODO: Implement else if for ErrRevisionNotFound.
+		if err != nil {
+			w.Write([]byte("error getting form revision"))
+			return
+		}
+
+		// Map to API revision response.
+		res := &Revision{
+			Number:  sr.Number,
+			Author:  sr.Author,
+			Created: sr.Created,
+			Form: &Form{
+				ID:       sr.FormID,
+				Modules:  []interface{}{},
+				Pricing:  sr.Form.Pricing,
+				Revision: sr.Number,
+			},
+		}
+		for _, v := range sr.Form.Modules {
+			res.Form.Modules = append(res.Form.Modules, v)
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleRevert is the HTTP handler function for reverting a form to one of
+// its revisions, which is saved as a new revision. The If-Match header must
+// hold the ETag of the version being reverted.
+func HandleRevert(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the version being reverted.
+		version, ok := ifMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Get the form ID and revision number.
+		id := httprouter.GetParam(r, "id")
+		number, err := strconv.Atoi(httprouter.GetParam(r, "number"))
+		if err != nil {
+			w.Write([]byte("error parsing revision number"))
+			return
+		}
+
+		// TODO: Get this member from the request context.
+
+		// Revert the form.
+		sf, err := ac.Services.Form.Revert(id, number, version, "")
+		// TODO: Implement else if for ErrRevisionNotFound.
+		switch {
+		case err == form.ErrVersionConflict:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("error reverting form, the form was changed by someone else"))
+			return
+		case err != nil:
+			w.Write([]byte("error reverting form"))
+			return
+		}
+
+		// Map to API form response.
+		res := &Form{
+			ID:                 sf.ID,
+			Modules:            []interface{}{},
+			Pricing:            sf.Pricing,
+			Revision:           sf.Revision,
+			Version:            sf.Version,
+			PublishedRevision:  sf.PublishedRevision,
+			UnpublishedChanges: sf.UnpublishedChanges,
+		}
+		for _, v := range sf.Modules {
+			res.Modules = append(res.Modules, v)
+		}
+
+		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleDiff is the HTTP handler function for getting the differences
+// between two revisions of a form, given by the from and to query
+// parameters. The to revision defaults to the current revision, and the
+// from revision to the one before it.
+func HandleDiff(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Get the revision numbers.
+		to, err := strconv.Atoi(r.URL.Query().Get("to"))
+		if err != nil {
+			sf, err := ac.Services.Form.GetByID(id)
+			// TODO: Implement else if for ErrFormNotFound.
+			if err != nil {
+				w.Write([]byte("error getting form"))
+				return
+			}
+			to = sf.Revision
+		}
+		from, err := strconv.Atoi(r.URL.Query().Get("from"))
+		if err != nil {
+			from = to - 1
+		}
+
+		// Get the diff.
+		sd, err := ac.Services.Form.Diff(id, from, to)
+		// TODO: Implement else if for ErrRevisionNotFound.
+		if err != nil {
+			w.Write([]byte("error getting form diff"))
+			return
+		}
+
+		// Map to API diff response.
+		res := &Diff{
+			From:    sd.From,
+			To:      sd.To,
+			Modules: []ModuleChange{},
+			Pricing: sd.Pricing,
+		}
+		for _, v := range sd.Modules {
+			res.Modules = append(res.Modules, ModuleChange{
+				ID:     v.ID,
+				Name:   v.Name,
+				Type:   v.Type,
+				Change: v.Change,
+				Fields: v.Fields,
+			})
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGetModule is the HTTP handler function for getting a module from
+// the working draft of a form.
+func HandleGetModule(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the form and module IDs.
+		id := httprouter.GetParam(r, "id")
+		moduleID := httprouter.GetParam(r, "moduleID")
+
+		// Get the module.
+		module, err := ac.Services.Form.GetModule(id, moduleID)
+		// TODO: Implement else if for ErrFormNotFound.
+		switch {
+		case err == types.ErrModuleNotFound:
+			w.Write([]byte("error getting module, module not found"))
+			return
+		case err != nil:
+			w.Write([]byte("error getting module"))
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, module); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleInsertModule is the HTTP handler function for inserting a module
+// into the working draft of a form. The If-Match header must hold the ETag
+// of the version being updated.
+func HandleInsertModule(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the version being updated.
+		version, ok := ifMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Parse the request body.
+		var mi ModuleInsert
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&mi); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// TODO: Get this member from the request context.
+
+		// Map module interface to module type.
+		modules, err := ac.Services.Form.InterfaceToModules([]interface{}{mi.Module})
+		if err != nil {
+			w.Write([]byte("error converting interface to module"))
+			return
+		}
+
+		// Handle position, append when left out.
+		position := -1
+		if mi.Position != nil {
+			position = *mi.Position
+		}
+
+		// Insert the module.
+		sf, module, err := ac.Services.Form.InsertModule(id, "", version, mi.ParentID, position, modules[0])
+		// TODO: Implement else if for ErrFormNotFound.
+		switch {
+		case err == form.ErrVersionConflict:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("error inserting module, the form was changed by someone else"))
+			return
+		case err == types.ErrModuleNotFound:
+			w.Write([]byte("error inserting module, parent module not found"))
+			return
+		case err != nil:
+			w.Write([]byte("error inserting module"))
+			return
+		}
+
+		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
+		if err := response.JSON(w, true, module); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleUpdateModule is the HTTP handler function for replacing a module in
+// the working draft of a form. The module keeps its ID. The If-Match header
+// must hold the ETag of the version being updated.
+func HandleUpdateModule(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the version being updated.
+		version, ok := ifMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Parse the request body.
+		var m interface{}
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&m); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Get the form and module IDs.
+		id := httprouter.GetParam(r, "id")
+		moduleID := httprouter.GetParam(r, "moduleID")
+
+		// TODO: Get this member from the request context.
+
+		// Map module interface to module type.
+		modules, err := ac.Services.Form.InterfaceToModules([]interface{}{m})
+		if err != nil {
+			w.Write([]byte("error converting interface to module"))
+			return
+		}
+
+		// Update the module.
+		sf, module, err := ac.Services.Form.UpdateModule(id, "", version, moduleID, modules[0])
+		// TODO: Implement else if for ErrFormNotFound.
+		switch {
+		case err == form.ErrVersionConflict:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("error updating module, the form was changed by someone else"))
+			return
+		case err == types.ErrModuleNotFound:
+			w.Write([]byte("error updating module, module not found"))
+			return
+		case err != nil:
+			w.Write([]byte("error updating module"))
+			return
+		}
+
+		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
+		if err := response.JSON(w, true, module); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleDeleteModule is the HTTP handler function for deleting a module,
+// along with its children, from the working draft of a form. The If-Match
+// header must hold the ETag of the version being updated.
+func HandleDeleteModule(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the version being updated.
+		version, ok := ifMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Get the form and module IDs.
+		id := httprouter.GetParam(r, "id")
+		moduleID := httprouter.GetParam(r, "moduleID")
+
+		// TODO: Get this member from the request context.
+
+		// Delete the module.
+		sf, err := ac.Services.Form.DeleteModule(id, "", version, moduleID)
+		// TODO: Implement else if for ErrFormNotFound.
+		switch {
+		case err == form.ErrVersionConflict:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("error deleting module, the form was changed by someone else"))
+			return
+		case err == types.ErrModuleNotFound:
+			w.Write([]byte("error deleting module, module not found"))
+			return
+		case err != nil:
+			w.Write([]byte("error deleting module"))
+			return
+		}
+
+		// Map to API form response.
+		res := &Form{
+			ID:                 sf.ID,
+			Modules:            []interface{}{},
+			Pricing:            sf.Pricing,
+			Revision:           sf.Revision,
+			Version:            sf.Version,
+			PublishedRevision:  sf.PublishedRevision,
+			UnpublishedChanges: sf.UnpublishedChanges,
+		}
+		for _, v := range sf.Modules {
+			res.Modules = append(res.Modules, v)
+		}
+
+		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleReorderModules is the HTTP handler function for reordering the
+// modules of the working draft of a form, either the top level modules or
+// the children of a section or repeater. The If-Match header must hold the
+// ETag of the version being updated.
+func HandleReorderModules(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the version being updated.
+		version, ok := ifMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Parse the request body.
+		var mo ModuleOrder
+		if err := json.NewDecoder(r.Body).Decode(&mo); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// TODO: Get this member from the request context.
+
+		// Reorder the modules.
+		sf, err := ac.Services.Form.ReorderModules(id, "", version, mo.ParentID, mo.Order)
+		// TODO: Implement else if for ErrFormNotFound.
+		switch {
+		case err == form.ErrVersionConflict:
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte("error reordering modules, the form was changed by someone else"))
+			return
+		case err == types.ErrModuleNotFound:
+			w.Write([]byte("error reordering modules, parent module not found"))
+			return
+		case err != nil:
+			w.Write([]byte("error reordering modules"))
+			return
+		}
+
+		// Map to API form response.
+		res := &Form{
+			ID:                 sf.ID,
+			Modules:            []interface{}{},
+			Pricing:            sf.Pricing,
+			Revision:           sf.Revision,
+			Version:            sf.Version,
+			PublishedRevision:  sf.PublishedRevision,
+			UnpublishedChanges: sf.UnpublishedChanges,
+		}
+		for _, v := range sf.Modules {
+			res.Modules = append(res.Modules, v)
+		}
+
+		// Respond with JSON.
+		w.Header().Set("ETag", etag(sf.Version))
 		if err := response.JSON(w, true, res); err != nil {
 			// TODO: Use logger.
 			fmt.Printf("error in handler: %v\n", err)
@@ -0,0 +1,394 @@
+package quote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apictx "estimator/cmd/api/context"
+	"estimator/cmd/api/response"
+	"estimator/cmd/api/v1/branding"
+	"estimator/cmd/api/v1/handlers/estimate"
+	"estimator/render/pdf"
+	"estimator/types"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Request defines the quote create request. The coupon and currency are
+// applied as for an estimate. ExpiresAt is optional. RequireSignature
+// requires customers to sign to accept the quote.
+type Request struct {
+	FormID           string                 `json:"form_id"`
+	Answers          map[string]interface{} `json:"answers"`
+	Coupon           string                 `json:"coupon"`
+	Currency         string                 `json:"currency"`
+	ExpiresAt        time.Time              `json:"expires_at"`
+	RequireSignature bool                   `json:"require_signature"`
+}
+
+// StatusRequest defines the quote status request.
+type StatusRequest struct {
+	Status string `json:"status"`
+}
+
+// RespondRequest defines the public quote accept and decline request. The
+// name is required to accept, and the signature, a signature module answer,
+// when the quote requires one.
+type RespondRequest struct {
+	Name      string                 `json:"name"`
+	Signature map[string]interface{} `json:"signature"`
+}
+
+// Quote defines the quote response. FormRevision and FormVersion are the
+// revision and version of the form the quote was made from. The token and
+// the response IP and user agent are left out of public responses.
+type Quote struct {
+	ID               string                 `json:"id"`
+	Number           string                 `json:"number"`
+	Token            string                 `json:"token,omitempty"`
+	FormID           string                 `json:"form_id"`
+	FormRevision     int                    `json:"form_revision"`
+	FormVersion      int                    `json:"form_version"`
+	Form             Form                   `json:"form"`
+	Answers          map[string]interface{} `json:"answers"`
+	Estimate         *estimate.Estimate     `json:"estimate"`
+	Status           string                 `json:"status"`
+	ExpiresAt        time.Time              `json:"expires_at"`
+	RequireSignature bool                   `json:"require_signature"`
+	Response         *Response              `json:"response"`
+	Created          time.Time              `json:"created"`
+	Updated          time.Time              `json:"updated"`
+}
+
+// Response defines the customer response of a quote response.
+type Response struct {
+	Status    string                 `json:"status"`
+	Name      string                 `json:"name"`
+	Signature map[string]interface{} `json:"signature"`
+	IP        string                 `json:"ip,omitempty"`
+	UserAgent string                 `json:"user_agent,omitempty"`
+	At        time.Time              `json:"at"`
+}
+
+// Form defines the form snapshot response of a quote.
+type Form struct {
+	Modules []interface{} `json:"modules"`
+	Pricing interface{}   `json:"pricing"`
+}
+
+// New creates a new quote handler.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.POST("/api/v1/quotes", HandleCreate(ac))
+	router.GET("/api/v1/quotes", HandleGet(ac))
+	router.GET("/api/v1/quotes/:id", HandleGetByID(ac))
+	router.POST("/api/v1/quotes/:id/status", HandleStatus(ac))
+	router.GET("/api/v1/public/quotes/:token", HandlePublicGet(ac))
+	router.POST("/api/v1/public/quotes/:token/accept", HandleRespond(ac, types.QuoteAccepted))
+	router.POST("/api/v1/public/quotes/:token/decline", HandleRespond(ac, types.QuoteDeclined))
+}
+
+// fromType maps a quote to a quote response.
+func fromType(q *types.Quote) *Quote {
+	res := &Quote{
+		ID:           q.ID,
+		Number:       q.Number,
+		Token:        q.Token,
+		FormID:       q.Form.ID,
+		FormRevision: q.Form.Revision,
+		FormVersion:  q.Form.Version,
+		Form: Form{
+			Modules: []interface{}{},
+			Pricing: q.Form.Pricing,
+		},
+		Answers:          q.Answers,
+		Estimate:         estimate.FromType(q.Estimate, q.Estimate.Locale),
+		Status:           q.Status,
+		ExpiresAt:        q.ExpiresAt,
+		RequireSignature: q.RequireSignature,
+		Created:          q.Created,
+		Updated:          q.Updated,
+	}
+	for _, v := range q.Form.Modules {
+		res.Form.Modules = append(res.Form.Modules, v)
+	}
+	if q.Response != nil {
+		res.Response = &Response{
+			Status:    q.Response.Status,
+			Name:      q.Response.Name,
+			Signature: q.Response.Signature,
+			IP:        q.Response.IP,
+			UserAgent: q.Response.UserAgent,
+			At:        q.Response.At,
+		}
+	}
+
+	return res
+}
+
+// fromPublicType maps a quote to a public quote response, leaving out the
+// token, the form coupons and the response IP and user agent, including any
+// IP recorded in the signature.
+func fromPublicType(q *types.Quote) *Quote {
+	res := fromType(q)
+	res.Token = ""
+	if q.Form.Pricing != nil {
+		p := *q.Form.Pricing
+		p.Coupons = nil
+		res.Form.Pricing = &p
+	}
+	if res.Response != nil {
+		res.Response.IP = ""
+		res.Response.UserAgent = ""
+		res.Response.Signature = publicSignature(q.Response.Signature)
+	}
+
+	return res
+}
+
+// publicSignature returns a copy of the signature without the IP, which
+// quotes responded to before it was only kept on the response still have.
+func publicSignature(signature map[string]interface{}) map[string]interface{} {
+	if signature == nil {
+		return nil
+	}
+
+	res := map[string]interface{}{}
+	for k, v := range signature {
+		if k != "ip" {
+			res[k] = v
+		}
+	}
+
+	return res
+}
+
+// HandleCreate is the HTTP handler function for creating a quote.
+func HandleCreate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the request body.
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Create a new services quote.
+		sq, err := ac.Services.Quote.Create(req.FormID, req.Answers, req.Coupon, req.Currency, req.ExpiresAt, req.RequireSignature)
+		// TODO: Implement else if for ErrFormNotFound.
+		if err != nil {
+			w.Write([]byte("error creating quote"))
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, fromType(sq)); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGet is the HTTP handler function for getting a page of quotes,
+// optionally filtered by the form_id and status query parameters.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the limit and offset.
+		limit := ac.Config.LimitDefault
+		if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+			limit = v
+		}
+		if limit > ac.Config.LimitMax {
+			limit = ac.Config.LimitMax
+		}
+		offset := 0
+		if v, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && v > 0 {
+			offset = v
+		}
+
+		// Get the quotes.
+		sqs, err := ac.Services.Quote.Get(r.URL.Query().Get("form_id"), r.URL.Query().Get("status"), limit, offset)
+		if err != nil {
+			w.Write([]byte("error getting quotes"))
+			return
+		}
+
+		// Map to API quote responses.
+		res := []*Quote{}
+		for _, sq := range sqs {
+			res = append(res, fromType(sq))
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGetByID is the HTTP handler function for getting a quote. The quote
+// is rendered as a PDF when the ID ends in ".pdf", as the router can't match
+// the extension separately.
+func HandleGetByID(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the quote ID.
+		id := httprouter.GetParam(r, "id")
+		isPDF := strings.HasSuffix(id, ".pdf")
+		id = strings.TrimSuffix(id, ".pdf")
+
+		// Get the quote.
+		sq, err := ac.Services.Quote.GetByID(id)
+		// TODO: Implement else if for ErrQuoteNotFound.
+		if err != nil {
+			w.Write([]byte("error getting quote"))
+			return
+		}
+
+		// Handle PDF requests.
+		if isPDF {
+			writePDF(ac, w, sq)
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, fromType(sq)); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandlePublicGet is the HTTP handler function for customers getting a
+// quote by its public token. The quote is rendered as a PDF when the token
+// ends in ".pdf".
+func HandlePublicGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the quote token.
+		token := httprouter.GetParam(r, "token")
+		isPDF := strings.HasSuffix(token, ".pdf")
+		token = strings.TrimSuffix(token, ".pdf")
+
+		// Get the quote.
+		sq, err := ac.Services.Quote.GetByToken(token)
+		// TODO: Implement else if for ErrQuoteNotFound.
+		if err != nil {
+			w.Write([]byte("error getting quote"))
+			return
+		}
+
+		// Handle PDF requests.
+		if isPDF {
+			if sq.Response != nil {
+				res := *sq.Response
+				res.Signature = publicSignature(res.Signature)
+				sq.Response = &res
+			}
+			writePDF(ac, w, sq)
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, fromPublicType(sq)); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleRespond is the HTTP handler function for customers accepting or
+// declining a quote by its public token, depending on the given status.
+func HandleRespond(ac *apictx.Context, status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the request body.
+		var req RespondRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Get the quote token.
+		token := httprouter.GetParam(r, "token")
+
+		// Get the client IP.
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		// Record the response.
+		sq, err := ac.Services.Quote.Respond(token, status, req.Name, req.Signature, ip, r.UserAgent())
+		// TODO: Implement else if for ErrQuoteNotFound.
+		switch {
+		case err == types.ErrInvalidTransition:
+			w.Write([]byte("error responding to quote, quote is no longer open"))
+			return
+		case err != nil:
+			w.Write([]byte("error responding to quote"))
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, fromPublicType(sq)); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// writePDF renders the quote as a PDF to the response.
+func writePDF(ac *apictx.Context, w http.ResponseWriter, q *types.Quote) {
+	b, err := branding.Get(ac)
+	if err != nil {
+		w.Write([]byte("error getting branding"))
+		return
+	}
+	buf := &bytes.Buffer{}
+	if err := pdf.Quote(buf, q, b); err != nil {
+		w.Write([]byte("error rendering quote"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="`+q.Number+`.pdf"`)
+	w.Write(buf.Bytes())
+}
+
+// HandleStatus is the HTTP handler function for moving a quote to another
+// status.
+func HandleStatus(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the request body.
+		var req StatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Get the quote ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Update the status.
+		sq, err := ac.Services.Quote.Transition(id, req.Status)
+		// TODO: Implement else if for ErrQuoteNotFound.
+		switch {
+		case err == types.ErrInvalidTransition:
+			w.Write([]byte("error updating quote status, invalid transition"))
+			return
+		case err != nil:
+			w.Write([]byte("error updating quote status"))
+			return
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, fromType(sq)); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package submission
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apictx "estimator/cmd/api/context"
+	"estimator/cmd/api/response"
+	"estimator/cmd/api/v1/branding"
+	"estimator/render/pdf"
+	"estimator/types"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Submission defines the submission request/response.
+type Submission struct {
+	ID           string                 `json:"id"`
+	FormID       string                 `json:"form_id"`
+	FormRevision int                    `json:"form_revision"`
+	Answers      map[string]interface{} `json:"answers"`
+	Prefill      map[string]string      `json:"prefill"`
+	Referrer     string                 `json:"referrer"`
+	IP           string                 `json:"ip"`
+	Coupon       string                 `json:"coupon"`
+	Created      time.Time              `json:"created"`
+}
+
+// Validation defines the page validation response.
+type Validation struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// New creates a new submission handler.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.POST("/api/v1/form/:id/submission", HandleCreate(ac))
+	router.POST("/api/v1/form/:id/pages/:page/validate", HandleValidatePage(ac))
+	router.GET("/api/v1/submission/:id", HandleGet(ac))
+}
+
+// HandleCreate is the HTTP handler function for submitting a form.
+func HandleCreate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the request body.
+		var s Submission
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Get the form ID.
+		id := httprouter.GetParam(r, "id")
+
+		// Get the client IP.
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		// Create a new services submission.
+		ss, err := ac.Services.Submission.Create(&types.Submission{
+			FormID:   id,
+			Answers:  s.Answers,
+			Prefill:  s.Prefill,
+			Referrer: s.Referrer,
+			IP:       ip,
+			Coupon:   s.Coupon,
+		})
+		// TODO: Implement else if for ErrFormNotFound.
+		if err != nil {
+			w.Write([]byte("error creating submission"))
+			return
+		}
+
+		// Map to API submission response.
+		res := &Submission{
+			ID:           ss.ID,
+			FormID:       ss.FormID,
+			FormRevision: ss.FormRevision,
+			Answers:      ss.Answers,
+			Prefill:      ss.Prefill,
+			Referrer:     ss.Referrer,
+			IP:           ss.IP,
+			Coupon:       ss.Coupon,
+			Created:      ss.Created,
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleValidatePage is the HTTP handler function for validating the answers
+// to a single page of a form.
+func HandleValidatePage(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the request body.
+		var s Submission
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			w.Write([]byte("error decoding request body"))
+			return
+		}
+
+		// Get the form ID and page number.
+		id := httprouter.GetParam(r, "id")
+		page, err := strconv.Atoi(httprouter.GetParam(r, "page"))
+		if err != nil {
+			w.Write([]byte("error parsing page number"))
+			return
+		}
+
+		// Validate the page.
+		res := &Validation{
+			Valid: true,
+		}
+		if err := ac.Services.Submission.ValidatePage(id, page, s.Answers); err != nil {
+			res.Valid = false
+			res.Error = err.Error()
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
+
+// HandleGet is the HTTP handler function for getting a submission. A summary
+// of the submission is rendered as a PDF when the ID ends in ".pdf", as the
+// router can't match the extension separately.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the submission ID.
+		id := httprouter.GetParam(r, "id")
+		isPDF := strings.HasSuffix(id, ".pdf")
+		id = strings.TrimSuffix(id, ".pdf")
+
+		// Get the submission.
+		ss, err := ac.Services.Submission.GetByID(id)
+		// TODO: Implement else if for ErrSubmissionNotFound.
+		if err != nil {
+			w.Write([]byte("error getting submission"))
+			return
+		}
+
+		// Handle PDF requests, rendering the submission against the form
+		// revision it was made against.
+		if isPDF {
+			sr, err := ac.Services.Form.GetRevision(ss.FormID, ss.FormRevision)
+			if err != nil {
+				w.Write([]byte("error getting form"))
+				return
+			}
+			sf := sr.Form
+			b, err := branding.Get(ac)
+			if err != nil {
+				w.Write([]byte("error getting branding"))
+				return
+			}
+			buf := &bytes.Buffer{}
+			if err := pdf.Submission(buf, sf, ss, b); err != nil {
+				w.Write([]byte("error rendering submission"))
+				return
+			}
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Disposition", `inline; filename="submission-`+ss.ID+`.pdf"`)
+			w.Write(buf.Bytes())
+			return
+		}
+
+		// Map to API submission response.
+		res := &Submission{
+			ID:           ss.ID,
+			FormID:       ss.FormID,
+			FormRevision: ss.FormRevision,
+			Answers:      ss.Answers,
+			Prefill:      ss.Prefill,
+			Referrer:     ss.Referrer,
+			IP:           ss.IP,
+			Coupon:       ss.Coupon,
+			Created:      ss.Created,
+		}
+
+		// Respond with JSON.
+		if err := response.JSON(w, true, res); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error in handler: %v\n", err)
+		}
+	}
+}
@@ -2,7 +2,14 @@ package v1
 
 import (
 	apictx "estimator/cmd/api/context"
+	"estimator/cmd/api/v1/handlers/asset"
+	"estimator/cmd/api/v1/handlers/blob"
+	"estimator/cmd/api/v1/handlers/catalog"
+	"estimator/cmd/api/v1/handlers/estimate"
+	"estimator/cmd/api/v1/handlers/exchangerate"
 	"estimator/cmd/api/v1/handlers/form"
+	"estimator/cmd/api/v1/handlers/quote"
+	"estimator/cmd/api/v1/handlers/submission"
 
 	"github.com/beeker1121/httprouter"
 )
@@ -10,4 +17,11 @@ import (
 // New creates a new v1 API.
 func New(ac *apictx.Context, r *httprouter.Router) {
 	form.New(ac, r)
+	submission.New(ac, r)
+	blob.New(ac, r)
+	asset.New(ac, r)
+	catalog.New(ac, r)
+	estimate.New(ac, r)
+	exchangerate.New(ac, r)
+	quote.New(ac, r)
 }
@@ -1,5 +1,94 @@
 CREATE TABLE `forms` (
     `id` varchar(36) NOT NULL,
     `modules` JSON NOT NULL,
+    `pricing` JSON NOT NULL,
+    `revision` INT NOT NULL,
+    `version` INT NOT NULL,
+    `published_revision` INT NOT NULL,
     PRIMARY KEY (`id`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
\ No newline at end of file
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `form_revisions` (
+    `form_id` varchar(36) NOT NULL,
+    `number` INT NOT NULL,
+    `author` varchar(36) NOT NULL,
+    `modules` JSON NOT NULL,
+    `pricing` JSON NOT NULL,
+    `created` DATETIME NOT NULL,
+    PRIMARY KEY (`form_id`, `number`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `submissions` (
+    `id` varchar(36) NOT NULL,
+    `form_id` varchar(36) NOT NULL,
+    `answers` JSON NOT NULL,
+    `prefill` JSON NOT NULL,
+    `referrer` TEXT NOT NULL,
+    `ip` varchar(45) NOT NULL,
+    `coupon` varchar(64) NOT NULL,
+    `created` DATETIME NOT NULL,
+    `form_revision` INT NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `form_id` (`form_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `catalog_items` (
+    `id` varchar(36) NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `sku` varchar(64) NOT NULL,
+    `description` TEXT NOT NULL,
+    `unit_price` DECIMAL(18,6) NOT NULL,
+    `min_price` DECIMAL(18,6) NOT NULL,
+    `max_price` DECIMAL(18,6) NOT NULL,
+    `unit` varchar(32) NOT NULL,
+    `image` varchar(64) NOT NULL,
+    `min_quantity` INT NOT NULL,
+    `max_quantity` INT NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `sku` (`sku`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `coupon_redemptions` (
+    `form_id` varchar(36) NOT NULL,
+    `code` varchar(64) NOT NULL,
+    `uses` INT NOT NULL,
+    PRIMARY KEY (`form_id`, `code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `exchange_rates` (
+    `base` char(3) NOT NULL,
+    `quote` char(3) NOT NULL,
+    `rate` DECIMAL(18,6) NOT NULL,
+    `updated` DATETIME NOT NULL,
+    PRIMARY KEY (`base`, `quote`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `quotes` (
+    `id` varchar(36) NOT NULL,
+    `number` varchar(16) NOT NULL,
+    `form_id` varchar(36) NOT NULL,
+    `form_modules` JSON NOT NULL,
+    `form_pricing` JSON NOT NULL,
+    `answers` JSON NOT NULL,
+    `estimate` JSON NOT NULL,
+    `status` varchar(16) NOT NULL,
+    `expires_at` DATETIME NOT NULL,
+    `created` DATETIME NOT NULL,
+    `updated` DATETIME NOT NULL,
+    `token` varchar(64) NOT NULL,
+    `require_signature` BOOLEAN NOT NULL,
+    `response` JSON NOT NULL,
+    `form_revision` INT NOT NULL,
+    `form_version` INT NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `number` (`number`),
+    UNIQUE KEY `token` (`token`),
+    KEY `form_id` (`form_id`),
+    KEY `status_expires_at` (`status`, `expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `quote_numbers` (
+    `year` INT NOT NULL,
+    `last` INT NOT NULL,
+    PRIMARY KEY (`year`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,332 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"estimator/types"
+)
+
+// fullNameParts defines the parts of a full name answer, in the order they
+// are shown.
+var fullNameParts = []string{"prefix", "first_name", "middle_name", "last_name", "suffix"}
+
+// answers draws the answers to the given modules as labelled fields,
+// skipping unanswered modules and hidden sections. Sections and repeater
+// items are drawn under their own titles. Conditions on dates are evaluated
+// in the given time zones. Signatures are drawn when signatures is true, and
+// otherwise only named.
+func (l *layout) answers(modules []types.Module, answers map[string]interface{}, locations types.Locations, signatures bool) error {
+	for _, module := range modules {
+		switch m := module.(type) {
+		case *types.Heading, *types.Hidden, *types.PageBreak:
+			continue
+		case *types.Section:
+			// Handle sections, only when visible.
+			if !m.Visible(answers, locations) {
+				continue
+			}
+			if m.Properties.Title != "" {
+				l.ensure(30)
+				l.space(6)
+				l.paragraph(margin, contentWide, Bold, 10, black, m.Properties.Title)
+				l.space(3)
+			}
+			if err := l.answers(m.Modules, answers, locations, signatures); err != nil {
+				return err
+			}
+			continue
+		case *types.Repeater:
+			// Handle each repeater item.
+			items, err := m.Items(answers[m.Name])
+			if err != nil {
+				continue
+			}
+			label := m.Properties.ItemLabel
+			if label == "" {
+				label = m.Properties.Label
+			}
+			for i, item := range items {
+				l.ensure(30)
+				l.space(6)
+				l.paragraph(margin, contentWide, Bold, 10, black, fmt.Sprintf("%s %d", label, i+1))
+				l.space(3)
+				if err := l.answers(m.Modules, item, locations, signatures); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// Get the answer.
+		answer, ok := answers[module.GetName()]
+		if !ok || answer == nil || answer == "" {
+			continue
+		}
+
+		// Handle signatures.
+		if sig, ok := module.(*types.Signature); ok && signatures {
+			if err := l.signature(sig, answer); err != nil {
+				return err
+			}
+			continue
+		}
+
+		l.field(moduleLabel(module), formatAnswer(module, answer))
+	}
+
+	return nil
+}
+
+// signature draws a signature answer from its image or strokes, followed
+// by the signer details. Signatures kept in the blob store are only named.
+func (l *layout) signature(sig *types.Signature, answer interface{}) error {
+	m, _ := answer.(map[string]interface{})
+	l.ensure(90)
+	l.space(6)
+	l.paragraph(margin, contentWide, Bold, 9, muted, moduleLabel(sig))
+	l.space(4)
+
+	// Draw the signature.
+	switch {
+	case m["image"] != nil:
+		dataURL, _ := m["image"].(string)
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(dataURL, "data:image/png;base64,"))
+		if err != nil {
+			return err
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		_, h, err := l.image(img, margin, 200, 60)
+		if err != nil {
+			return err
+		}
+		l.space(h)
+	case m["strokes"] != nil:
+		l.strokes(m["strokes"], margin, 200, 60)
+		l.space(60)
+	}
+	l.rule(light)
+
+	// Draw the signer details.
+	details := []string{}
+	if name, _ := m["name"].(string); name != "" {
+		details = append(details, "Signed by "+name)
+	}
+	if signedAt, _ := m["signed_at"].(string); signedAt != "" {
+		details = append(details, "at "+signedAt)
+	}
+	if ip, _ := m["ip"].(string); ip != "" {
+		details = append(details, "from "+ip)
+	}
+	if len(details) > 0 {
+		l.paragraph(margin, contentWide, Regular, 8, muted, strings.Join(details, " "))
+	}
+	l.space(6)
+
+	return nil
+}
+
+// strokes draws signature strokes scaled to fit within the given width and
+// height below the current position.
+func (l *layout) strokes(v interface{}, x, width, height float64) {
+	// Get the points of each stroke and their bounds.
+	strokes := [][][2]float64{}
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	strokesSlice, _ := v.([]interface{})
+	for _, s := range strokesSlice {
+		pointsSlice, _ := s.([]interface{})
+		points := [][2]float64{}
+		for _, p := range pointsSlice {
+			point, _ := p.(map[string]interface{})
+			px, _ := point["x"].(float64)
+			py, _ := point["y"].(float64)
+			points = append(points, [2]float64{px, py})
+			minX, minY = math.Min(minX, px), math.Min(minY, py)
+			maxX, maxY = math.Max(maxX, px), math.Max(maxY, py)
+		}
+		strokes = append(strokes, points)
+	}
+	if math.IsInf(minX, 0) {
+		return
+	}
+
+	// Scale the points into the box.
+	scale := math.Min(width/math.Max(maxX-minX, 1), height/math.Max(maxY-minY, 1))
+	for _, points := range strokes {
+		for i, p := range points {
+			points[i] = [2]float64{x + (p[0]-minX)*scale, l.y + (p[1]-minY)*scale}
+		}
+		l.d.Polyline(points, 1.2, black)
+	}
+}
+
+// moduleLabel gets the label shown for a module, defaulting to its name.
+func moduleLabel(module types.Module) string {
+	label := ""
+	switch m := module.(type) {
+	case *types.ShortText:
+		label = m.Properties.Label
+	case *types.MultipleChoice:
+		label = m.Properties.Label
+	case *types.FullName:
+		label = m.Properties.Label
+	case *types.Date:
+		label = m.Properties.Label
+	case *types.Time:
+		label = m.Properties.Label
+	case *types.DateRange:
+		label = m.Properties.Label
+	case *types.FileUpload:
+		label = m.Properties.Label
+	case *types.Slider:
+		label = m.Properties.Label
+	case *types.Rating:
+		label = m.Properties.Label
+	case *types.Matrix:
+		label = m.Properties.Label
+	case *types.Signature:
+		label = m.Properties.Label
+	case *types.Consent:
+		label = m.Properties.Label
+	case *types.ProductList:
+		label = m.Properties.Label
+	case *types.Repeater:
+		label = m.Properties.Label
+	}
+	if label == "" {
+		return module.GetName()
+	}
+
+	return label
+}
+
+// formatAnswer formats an answer to a module as text.
+func formatAnswer(module types.Module, answer interface{}) string {
+	switch m := module.(type) {
+	case *types.FullName:
+		parts := []string{}
+		name, _ := answer.(map[string]interface{})
+		for _, k := range fullNameParts {
+			if s, _ := name[k].(string); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, " ")
+	case *types.DateRange:
+		dr, _ := answer.(map[string]interface{})
+		return fmt.Sprintf("%v – %v", dr["start"], dr["end"])
+	case *types.FileUpload:
+		ids, _ := answer.([]interface{})
+		if len(ids) == 1 {
+			return "1 file"
+		}
+		return fmt.Sprintf("%d files", len(ids))
+	case *types.Rating:
+		return fmt.Sprintf("%s of %d", formatValue(answer), m.Properties.Scale)
+	case *types.Matrix:
+		return formatMatrix(m, answer)
+	case *types.Consent:
+		c, _ := answer.(map[string]interface{})
+		if accepted, _ := c["accepted"].(bool); accepted {
+			return "Accepted"
+		}
+		return "Not accepted"
+	case *types.Signature:
+		sig, _ := answer.(map[string]interface{})
+		if name, _ := sig["name"].(string); name != "" {
+			return "Signed by " + name
+		}
+		return "Signed"
+	case *types.ProductList:
+		n := 0
+		for _, q := range m.Quantities(answer) {
+			n += q
+		}
+		if n == 1 {
+			return "1 item"
+		}
+		return fmt.Sprintf("%d items", n)
+	}
+
+	return formatValue(answer)
+}
+
+// formatMatrix formats a matrix answer as a row per line, with the row
+// label and the selected columns or entered numbers.
+func formatMatrix(mx *types.Matrix, answer interface{}) string {
+	rows, _ := answer.(map[string]interface{})
+	columns := map[string]string{}
+	for _, v := range mx.Properties.Columns {
+		columns[v.ID] = v.Label
+	}
+
+	lines := []string{}
+	for _, row := range mx.Properties.Rows {
+		v, ok := rows[row.ID]
+		if !ok || v == nil {
+			continue
+		}
+		cells := []string{}
+		switch cell := v.(type) {
+		case string:
+			cells = append(cells, columns[cell])
+		case []interface{}:
+			for _, c := range cell {
+				id, _ := c.(string)
+				cells = append(cells, columns[id])
+			}
+		case map[string]interface{}:
+			for _, column := range mx.Properties.Columns {
+				if n, ok := cell[column.ID]; ok {
+					cells = append(cells, column.Label+" "+formatValue(n))
+				}
+			}
+		}
+		lines = append(lines, row.Label+": "+strings.Join(cells, ", "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatValue formats a decoded JSON value as text.
+func formatValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		if t {
+			return "Yes"
+		}
+		return "No"
+	case []interface{}:
+		values := []string{}
+		for _, e := range t {
+			values = append(values, formatValue(e))
+		}
+		return strings.Join(values, ", ")
+	case map[string]interface{}:
+		keys := []string{}
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := []string{}
+		for _, k := range keys {
+			values = append(values, k+": "+formatValue(t[k]))
+		}
+		return strings.Join(values, ", ")
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,246 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Page size, A4 in points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document defines a PDF document being drawn. Positions are in points from
+// the top left corner of the page. The output only depends on what is
+// drawn, so the same document always produces the same bytes.
+type Document struct {
+	title   string
+	pages   []*bytes.Buffer
+	current int
+	images  []pdfImage
+}
+
+// pdfImage defines an image added to a document as compressed RGB data.
+type pdfImage struct {
+	width  int
+	height int
+	data   []byte
+}
+
+// New creates a new document with the given title and no pages.
+func New(title string) *Document {
+	return &Document{
+		title: title,
+	}
+}
+
+// AddPage adds a new page, which is then drawn on.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.current = len(d.pages) - 1
+}
+
+// PageCount returns the number of pages.
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// SetPage sets the page to draw on, numbered from 1, such as to add footers
+// once every page is drawn.
+func (d *Document) SetPage(n int) {
+	d.current = n - 1
+}
+
+// Text draws the string with its baseline starting at the given position.
+// Gray is from 0 for black to 1 for white.
+func (d *Document) Text(x, y float64, f Font, size, gray float64, s string) {
+	fmt.Fprintf(d.pages[d.current], "BT %s g /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		num(gray), f+1, num(size), num(x), num(PageHeight-y), escape(encode(s)))
+}
+
+// Line draws a line between the given positions.
+func (d *Document) Line(x1, y1, x2, y2, width, gray float64) {
+	fmt.Fprintf(d.pages[d.current], "%s w %s G %s %s m %s %s l S\n",
+		num(width), num(gray), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// Polyline draws connected lines through the given points, with round caps
+// and joins, such as a signature stroke.
+func (d *Document) Polyline(points [][2]float64, width, gray float64) {
+	if len(points) < 2 {
+		return
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "q 1 J 1 j %s w %s G %s %s m", num(width), num(gray), num(points[0][0]), num(PageHeight-points[0][1]))
+	for _, p := range points[1:] {
+		fmt.Fprintf(b, " %s %s l", num(p[0]), num(PageHeight-p[1]))
+	}
+	b.WriteString(" S Q\n")
+	d.pages[d.current].WriteString(b.String())
+}
+
+// FillRect fills the rectangle with its top left corner at the given
+// position.
+func (d *Document) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.pages[d.current], "%s g %s %s %s %s re f\n",
+		num(gray), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Image draws the image scaled into the rectangle with its top left corner
+// at the given position. Transparent pixels are drawn on white.
+func (d *Document) Image(img image.Image, x, y, w, h float64) error {
+	// Get the RGB data.
+	bounds := img.Bounds()
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			r, g, b, a := img.At(px, py).RGBA()
+			white := 0xffff - a
+			rgb = append(rgb, byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8))
+		}
+	}
+
+	// Compress the data.
+	data, err := compress(rgb)
+	if err != nil {
+		return err
+	}
+	d.images = append(d.images, pdfImage{
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		data:   data,
+	})
+
+	fmt.Fprintf(d.pages[d.current], "q %s 0 0 %s %s %s cm /Im%d Do Q\n",
+		num(w), num(h), num(x), num(PageHeight-y-h), len(d.images))
+
+	return nil
+}
+
+// WriteTo writes the document as a PDF file.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	b := &bytes.Buffer{}
+	offsets := []int{}
+
+	// object starts the next object, recording its offset.
+	object := func() int {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(b, "%d 0 obj\n", len(offsets))
+		return len(offsets)
+	}
+	// stream writes a stream object with the given dictionary entries.
+	stream := func(dict string, data []byte) {
+		object()
+		fmt.Fprintf(b, "<< %s/Length %d >>\nstream\n", dict, len(data))
+		b.Write(data)
+		b.WriteString("\nendstream\nendobj\n")
+	}
+
+	// Get the object numbers. The catalog, pages, info and fonts come
+	// first, then the images, then each page and its contents.
+	fonts := 4
+	firstImage := fonts + len(fontNames)
+	firstPage := firstImage + len(d.images)
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Handle the catalog, pages and info.
+	object()
+	b.WriteString("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	object()
+	kids := []string{}
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+i*2))
+	}
+	fmt.Fprintf(b, "<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(d.pages))
+	object()
+	fmt.Fprintf(b, "<< /Title (%s) /Producer (estimator) >>\nendobj\n", escape(encode(d.title)))
+
+	// Handle the fonts.
+	for f := Regular; f <= Bold; f++ {
+		object()
+		fmt.Fprintf(b, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\nendobj\n", fontNames[f])
+	}
+
+	// Handle the images.
+	for _, v := range d.images {
+		stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode ", v.width, v.height), v.data)
+	}
+
+	// Get the resources shared by the pages.
+	resources := fmt.Sprintf("/Font << /F1 %d 0 R /F2 %d 0 R >>", fonts, fonts+1)
+	if len(d.images) > 0 {
+		xobjects := []string{}
+		for i := range d.images {
+			xobjects = append(xobjects, fmt.Sprintf("/Im%d %d 0 R", i+1, firstImage+i))
+		}
+		resources += " /XObject << " + strings.Join(xobjects, " ") + " >>"
+	}
+
+	// Handle the pages.
+	for i, v := range d.pages {
+		object()
+		fmt.Fprintf(b, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << %s >> /Contents %d 0 R >>\nendobj\n",
+			num(PageWidth), num(PageHeight), resources, firstPage+i*2+1)
+		data, err := compress(v.Bytes())
+		if err != nil {
+			return 0, err
+		}
+		stream("/Filter /FlateDecode ", data)
+	}
+
+	// Handle the cross-reference table and trailer.
+	xref := b.Len()
+	fmt.Fprintf(b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, v := range offsets {
+		fmt.Fprintf(b, "%010d 00000 n \n", v)
+	}
+	fmt.Fprintf(b, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return b.WriteTo(w)
+}
+
+// compress compresses the data for the FlateDecode filter.
+func compress(data []byte) ([]byte, error) {
+	b := &bytes.Buffer{}
+	zw := zlib.NewWriter(b)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// escape escapes the encoded string for a PDF string literal.
+func escape(b []byte) string {
+	s := &strings.Builder{}
+	for _, c := range b {
+		if c == '(' || c == ')' || c == '\\' {
+			s.WriteByte('\\')
+		}
+		s.WriteByte(c)
+	}
+
+	return s.String()
+}
+
+// num formats a number with at most two decimal places.
+func num(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+
+	return s
+}
@@ -0,0 +1,164 @@
+package pdf
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Font defines one of the standard PDF fonts, which every PDF reader has,
+// so they don't need to be embedded.
+type Font int
+
+// Available fonts.
+const (
+	Regular Font = iota
+	Bold
+)
+
+// fontNames maps the fonts to their PDF base font names.
+var fontNames = map[Font]string{
+	Regular: "Helvetica",
+	Bold:    "Helvetica-Bold",
+}
+
+// fontWidths maps the fonts to the widths of the printable ASCII characters
+// from space to tilde, in thousandths of the font size.
+var fontWidths = map[Font][95]int{
+	Regular: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	Bold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// winAnsi maps the characters of Windows-1252 that differ from Latin-1 to
+// their byte.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// encodeByte gets the Windows-1252 byte for the given character, which is
+// how the standard fonts are encoded.
+func encodeByte(r rune) (byte, bool) {
+	switch {
+	case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+		return byte(r), true
+	case r == 0x202f:
+		// Narrow no-break spaces are used as group separators.
+		return ' ', true
+	}
+	b, ok := winAnsi[r]
+	return b, ok
+}
+
+// Encodable checks if every character of the string can be shown with the
+// standard fonts.
+func Encodable(s string) bool {
+	for _, r := range s {
+		if _, ok := encodeByte(r); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// encode encodes the string as Windows-1252, replacing the characters that
+// can't be shown with a question mark.
+func encode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		c, ok := encodeByte(r)
+		if !ok {
+			c = '?'
+		}
+		b = append(b, c)
+	}
+
+	return b
+}
+
+// charWidth gets the width of an encoded character in thousandths of the
+// font size. Characters outside of ASCII use the width of a digit.
+func charWidth(f Font, c byte) int {
+	switch {
+	case c >= 0x20 && c < 0x7f:
+		return fontWidths[f][c-0x20]
+	case c == 0xa0:
+		return fontWidths[f][0]
+	case c == 0x95:
+		return 350
+	case c == 0x97:
+		return 1000
+	}
+
+	return 556
+}
+
+// TextWidth gets the width of the string in points, in the given font and
+// size.
+func TextWidth(f Font, size float64, s string) float64 {
+	w := 0
+	for _, c := range encode(s) {
+		w += charWidth(f, c)
+	}
+
+	return float64(w) * size / 1000
+}
+
+// wrap splits the string into lines no wider than the given width, breaking
+// on spaces, and on existing line breaks. Words wider than the width are
+// broken between characters.
+func wrap(f Font, size, width float64, s string) []string {
+	lines := []string{}
+	for _, paragraph := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			// Break words that don't fit on a line of their own.
+			for TextWidth(f, size, word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				n := len(word)
+				for n > 1 && TextWidth(f, size, word[:n]) > width {
+					_, l := utf8.DecodeLastRuneInString(word[:n])
+					n -= l
+				}
+				lines = append(lines, word[:n])
+				word = word[n:]
+			}
+			if word == "" {
+				continue
+			}
+
+			// Add the word to the line, or start a new line.
+			switch {
+			case line == "":
+				line = word
+			case TextWidth(f, size, line+" "+word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
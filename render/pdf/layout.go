@@ -0,0 +1,183 @@
+package pdf
+
+import (
+	"fmt"
+	"image"
+)
+
+// Layout constants, in points.
+const (
+	margin      = 50
+	footer      = 30
+	contentWide = PageWidth - 2*margin
+	lineSpacing = 1.35
+)
+
+// Text colors, as gray levels.
+const (
+	black = 0
+	muted = 0.4
+	light = 0.85
+)
+
+// layout defines a top to bottom flow of content over the pages of a
+// document, starting new pages as needed.
+type layout struct {
+	d *Document
+	y float64
+}
+
+// newLayout creates a new layout on the first page of a new document.
+func newLayout(title string) *layout {
+	l := &layout{
+		d: New(title),
+	}
+	l.page()
+
+	return l
+}
+
+// page starts a new page.
+func (l *layout) page() {
+	l.d.AddPage()
+	l.y = margin
+}
+
+// ensure starts a new page unless there is room for the given height.
+func (l *layout) ensure(h float64) {
+	if l.y+h > PageHeight-margin-footer {
+		l.page()
+	}
+}
+
+// space adds vertical space.
+func (l *layout) space(h float64) {
+	l.y += h
+}
+
+// paragraph draws wrapped text across the given width from x.
+func (l *layout) paragraph(x, width float64, f Font, size, gray float64, s string) {
+	for _, line := range wrap(f, size, width, s) {
+		l.ensure(size * lineSpacing)
+		l.y += size * lineSpacing
+		l.d.Text(x, l.y-size*0.25, f, size, gray, line)
+	}
+}
+
+// title draws a section title with a rule under it.
+func (l *layout) title(s string) {
+	l.ensure(40)
+	l.space(12)
+	l.paragraph(margin, contentWide, Bold, 12, black, s)
+	l.space(4)
+	l.d.Line(margin, l.y, PageWidth-margin, l.y, 0.5, light)
+	l.space(4)
+}
+
+// field draws a label and its value side by side, wrapping the value.
+func (l *layout) field(label, value string) {
+	labelWidth := contentWide * 0.35
+	lines := wrap(Regular, 9, contentWide-labelWidth, value)
+	l.ensure(float64(len(lines)) * 9 * lineSpacing)
+
+	top := l.y
+	l.paragraph(margin, labelWidth-10, Bold, 9, muted, label)
+	bottom := l.y
+	l.y = top
+	l.paragraph(margin+labelWidth, contentWide-labelWidth, Regular, 9, black, value)
+	if bottom > l.y {
+		l.y = bottom
+	}
+	l.space(3)
+}
+
+// column defines a table column. Right aligned columns are used for
+// amounts.
+type column struct {
+	width float64
+	right bool
+}
+
+// row draws a table row, wrapping the cells to their columns.
+func (l *layout) row(columns []column, cells []string, f Font, size, gray float64) {
+	// Get the lines of each cell.
+	lines := [][]string{}
+	height := 0
+	for i, v := range cells {
+		cl := wrap(f, size, columns[i].width-8, v)
+		lines = append(lines, cl)
+		if len(cl) > height {
+			height = len(cl)
+		}
+	}
+	h := float64(height) * size * lineSpacing
+	l.ensure(h + 6)
+
+	// Draw the cells.
+	x := float64(margin)
+	for i, cl := range lines {
+		for j, line := range cl {
+			y := l.y + float64(j+1)*size*lineSpacing - size*0.25 + 3
+			lx := x + 4
+			if columns[i].right {
+				lx = x + columns[i].width - 4 - TextWidth(f, size, line)
+			}
+			l.d.Text(lx, y, f, size, gray, line)
+		}
+		x += columns[i].width
+	}
+	l.y += h + 6
+}
+
+// rule draws a horizontal rule across the content.
+func (l *layout) rule(gray float64) {
+	l.d.Line(margin, l.y, PageWidth-margin, l.y, 0.5, gray)
+}
+
+// total draws a label and amount right aligned at the end of the content,
+// such as a subtotal.
+func (l *layout) total(label, amount string, f Font, size float64) {
+	l.ensure(size * lineSpacing)
+	l.y += size * lineSpacing
+	y := l.y - size*0.25
+	right := PageWidth - margin - 4
+	l.d.Text(right-TextWidth(f, size, amount), y, f, size, black, amount)
+	l.d.Text(right-150-TextWidth(f, size, label), y, f, size, muted, label)
+	l.space(2)
+}
+
+// image draws the image at x, scaled to fit within the given width and
+// height while keeping its aspect ratio, returning the drawn size.
+func (l *layout) image(img image.Image, x, maxWidth, maxHeight float64) (float64, float64, error) {
+	bounds := img.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	scale := maxWidth / w
+	if maxHeight/h < scale {
+		scale = maxHeight / h
+	}
+	if scale > 1 {
+		scale = 1
+	}
+	w, h = w*scale, h*scale
+
+	l.ensure(h)
+	if err := l.d.Image(img, x, l.y, w, h); err != nil {
+		return 0, 0, err
+	}
+
+	return w, h, nil
+}
+
+// footers draws the given text and the page number at the bottom of every
+// page, once every page is drawn.
+func (l *layout) footers(text string) {
+	n := l.d.PageCount()
+	for i := 1; i <= n; i++ {
+		l.d.SetPage(i)
+		y := PageHeight - margin + 10
+		l.d.Line(margin, y-12, PageWidth-margin, y-12, 0.5, light)
+		l.d.Text(margin, y, Regular, 8, muted, text)
+		page := fmt.Sprintf("Page %d of %d", i, n)
+		l.d.Text(PageWidth-margin-TextWidth(Regular, 8, page), y, Regular, 8, muted, page)
+	}
+}
@@ -0,0 +1,97 @@
+// Package pdf renders quotes and submissions as PDF documents, using only
+// the standard library.
+package pdf
+
+import (
+	"image"
+	"io"
+	"strings"
+	"time"
+
+	"estimator/types"
+)
+
+// Branding defines the branding of rendered documents. Logo is optional.
+// Terms are printed on quotes above the signature.
+type Branding struct {
+	Name  string
+	Logo  image.Image
+	Terms string
+}
+
+// header draws the document header, with the logo or business name on the
+// left and the document title and details on the right, followed by the
+// titles of the heading modules of the form.
+func (l *layout) header(b *Branding, title string, details [][2]string, modules []types.Module) error {
+	top := l.y
+
+	// Draw the logo or name.
+	left := 0.0
+	switch {
+	case b.Logo != nil:
+		_, h, err := l.image(b.Logo, margin, 180, 60)
+		if err != nil {
+			return err
+		}
+		left = h
+	case b.Name != "":
+		l.paragraph(margin, contentWide/2, Bold, 16, black, b.Name)
+		left = l.y - top
+	}
+
+	// Draw the title and details.
+	l.y = top
+	right := PageWidth - margin
+	l.y += 20
+	l.d.Text(right-TextWidth(Bold, 20, title), l.y-4, Bold, 20, black, title)
+	l.space(6)
+	for _, v := range details {
+		l.y += 12
+		s := v[0] + ": " + v[1]
+		l.d.Text(right-TextWidth(Regular, 9, s), l.y-3, Regular, 9, muted, s)
+	}
+	if top+left > l.y {
+		l.y = top + left
+	}
+	l.space(16)
+
+	// Draw the heading modules.
+	for _, module := range types.Flatten(modules) {
+		h, ok := module.(*types.Heading)
+		if !ok {
+			continue
+		}
+		if h.Properties.Title != "" {
+			l.paragraph(margin, contentWide, Bold, 14, black, h.Properties.Title)
+		}
+		if h.Properties.Sublabel != "" {
+			l.paragraph(margin, contentWide, Regular, 10, muted, h.Properties.Sublabel)
+		}
+		l.space(6)
+	}
+
+	return nil
+}
+
+// formatMoney formats an amount for the locale, using the currency code
+// when the currency symbol can't be shown with the standard fonts.
+func formatMoney(m types.Money, locale string) string {
+	s := m.Format(locale)
+	if !Encodable(s) {
+		return m.Currency + " " + m.String()
+	}
+
+	return s
+}
+
+// formatDate formats the date of a time in UTC, such as "2026-10-19".
+func formatDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// write writes the laid out document with the given footer.
+func (l *layout) write(w io.Writer, footer ...string) error {
+	l.footers(strings.Join(footer, " · "))
+	_, err := l.d.WriteTo(w)
+	return err
+}
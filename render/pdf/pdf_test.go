@@ -0,0 +1,186 @@
+package pdf
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"estimator/types"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// created is the pinned creation date of the rendered documents.
+var created = time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+
+// testForm returns the form the documents are rendered from.
+func testForm() *types.Form {
+	return &types.Form{
+		ID: "4b1c7a9e-0d2f-4e5a-9c3b-6f8e2d1a7b40",
+		Modules: []types.Module{
+			&types.Heading{
+				ID:   "h1",
+				Type: "heading",
+				Name: "heading",
+				Properties: types.HeadingProperties{
+					Title:    "Roof Replacement",
+					Sublabel: "Tell us about your roof and we will get back to you.",
+				},
+			},
+			&types.ShortText{
+				ID:   "m1",
+				Type: "short-text",
+				Name: "address",
+				Properties: types.ShortTextProperties{
+					Label: "Address",
+				},
+			},
+			&types.MultipleChoice{
+				ID:   "m2",
+				Type: "multiple-choice",
+				Name: "material",
+				Properties: types.MultipleChoiceProperties{
+					Label: "Material",
+					Options: []types.MultipleChoiceOption{
+						{ID: "o1", Value: "Asphalt shingles"},
+						{ID: "o2", Value: "Metal"},
+					},
+				},
+			},
+			&types.Signature{
+				ID:   "m3",
+				Type: "signature",
+				Name: "signature",
+				Properties: types.SignatureProperties{
+					Label: "Signature",
+				},
+			},
+		},
+		Pricing: &types.Pricing{},
+	}
+}
+
+// testAnswers returns the answers to the test form.
+func testAnswers() map[string]interface{} {
+	return map[string]interface{}{
+		"address":  "12 Harbour Street, Portsmouth",
+		"material": "Metal",
+		"signature": map[string]interface{}{
+			"name":      "Jane Doe",
+			"signed_at": "2026-03-14T09:26:53Z",
+			"strokes": []interface{}{
+				[]interface{}{
+					map[string]interface{}{"x": 0.0, "y": 20.0},
+					map[string]interface{}{"x": 40.0, "y": 0.0},
+					map[string]interface{}{"x": 80.0, "y": 30.0},
+				},
+			},
+		},
+	}
+}
+
+// money returns the given amount in cents as US dollars.
+func money(cents int64) types.Money {
+	return types.Money{Amount: cents, Currency: "USD"}
+}
+
+// golden compares the rendered document to the golden file with the given
+// name, or updates the golden file when the -update flag is set.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file, run go test with -update if the change is intended", name)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	unitPrice, err := types.ParseDecimal("12.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	taxRate, err := types.ParseDecimal("8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := &types.Quote{
+		ID:      "9d3e1f7a-5b2c-4a6d-8e0f-1c2b3a4d5e6f",
+		Number:  types.QuoteNumber(created.Year(), 42),
+		Form:    testForm(),
+		Answers: testAnswers(),
+		Estimate: &types.Estimate{
+			Lines: []types.EstimateLine{
+				{
+					Kind:        types.LineItem,
+					Module:      "material",
+					Description: "Metal roofing",
+					SKU:         "MTL-24",
+					Quantity:    120,
+					Unit:        "sq ft",
+					UnitPrice:   unitPrice,
+					Amount:      money(150000),
+					Low:         money(135000),
+					High:        money(165000),
+				},
+				{
+					Kind:        types.LineTax,
+					Description: "Sales tax",
+					UnitPrice:   taxRate,
+					Amount:      money(12000),
+					Low:         money(10800),
+					High:        money(13200),
+				},
+			},
+			Currency: "USD",
+			Locale:   "en-US",
+			Subtotal: money(150000),
+			Tax:      money(12000),
+			Total:    money(162000),
+			Low:      money(145800),
+			High:     money(178200),
+		},
+		Status:    types.QuoteSent,
+		ExpiresAt: created.Add(types.DefaultQuoteValidity),
+		Created:   created,
+		Updated:   created,
+	}
+
+	var buf bytes.Buffer
+	if err := Quote(&buf, q, &Branding{Name: "Harbour Roofing", Terms: "Payment is due within 14 days of completion."}); err != nil {
+		t.Fatalf("rendering quote: %v", err)
+	}
+	golden(t, "quote.pdf", buf.Bytes())
+}
+
+func TestSubmission(t *testing.T) {
+	s := &types.Submission{
+		ID:           "2f6a8c0e-4b1d-4e3f-9a7c-5d2e1b0a9f8e",
+		FormID:       "4b1c7a9e-0d2f-4e5a-9c3b-6f8e2d1a7b40",
+		FormRevision: 3,
+		Answers:      testAnswers(),
+		Coupon:       "SPRING10",
+		Created:      created,
+	}
+
+	var buf bytes.Buffer
+	if err := Submission(&buf, testForm(), s, &Branding{Name: "Harbour Roofing"}); err != nil {
+		t.Fatalf("rendering submission: %v", err)
+	}
+	golden(t, "submission.pdf", buf.Bytes())
+}
@@ -0,0 +1,149 @@
+package pdf
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"estimator/types"
+)
+
+// Quote renders the quote as a PDF document, with the answers it was
+// created from, its line items and totals, the terms and a space for the
+// customer to sign, or their response once they have accepted or declined.
+func Quote(w io.Writer, q *types.Quote, b *Branding) error {
+	e := q.Estimate
+	l := newLayout("Quote " + q.Number)
+
+	// Handle the header.
+	details := [][2]string{
+		{"Number", q.Number},
+		{"Date", formatDate(q.Created)},
+		{"Valid until", formatDate(q.ExpiresAt)},
+	}
+	if q.Status != types.QuoteDraft && q.Status != types.QuoteSent {
+		details = append(details, [2]string{"Status", strings.ToUpper(q.Status[:1]) + q.Status[1:]})
+	}
+	if err := l.header(b, "Quote", details, q.Form.Modules); err != nil {
+		return err
+	}
+
+	// Handle the answers.
+	l.title("Details")
+	if err := l.answers(q.Form.Modules, q.Answers, types.DateLocations(q.Form.Modules), false); err != nil {
+		return err
+	}
+
+	// Handle the line items.
+	l.title("Items")
+	columns := []column{
+		{width: contentWide * 0.52},
+		{width: contentWide * 0.12, right: true},
+		{width: contentWide * 0.18, right: true},
+		{width: contentWide * 0.18, right: true},
+	}
+	l.d.FillRect(margin, l.y, contentWide, 9*lineSpacing+6, 0.95)
+	l.row(columns, []string{"Description", "Qty", "Unit price", "Amount"}, Bold, 9, black)
+	for _, v := range e.Lines {
+		if v.Kind != types.LineItem {
+			continue
+		}
+		description := v.Description
+		if v.SKU != "" {
+			description += " (" + v.SKU + ")"
+		}
+		if v.Low != v.High {
+			description += "\nEstimated " + formatMoney(v.Low, e.Locale) + " – " + formatMoney(v.High, e.Locale)
+		}
+		quantity := strconv.FormatFloat(v.Quantity, 'f', -1, 64)
+		if v.Unit != "" {
+			quantity += " " + v.Unit
+		}
+		l.row(columns, []string{description, quantity, formatUnitPrice(v.UnitPrice, e.Currency, e.Locale), formatMoney(v.Amount, e.Locale)}, Regular, 9, black)
+		l.rule(light)
+	}
+
+	// Handle the totals.
+	l.space(6)
+	l.total("Subtotal", formatMoney(e.Subtotal, e.Locale), Regular, 9)
+	for _, v := range e.Lines {
+		switch v.Kind {
+		case types.LineItem:
+			continue
+		case types.LineTax:
+			label := v.Description + " (" + v.UnitPrice.String() + "%)"
+			if v.Included {
+				label += ", included"
+			}
+			l.total(label, formatMoney(v.Amount, e.Locale), Regular, 9)
+		default:
+			l.total(v.Description, formatMoney(v.Amount, e.Locale), Regular, 9)
+		}
+	}
+	l.space(4)
+	l.total("Total", formatMoney(e.Total, e.Locale), Bold, 11)
+	if e.Low != e.High {
+		l.total("Estimated range", formatMoney(e.Low, e.Locale)+" – "+formatMoney(e.High, e.Locale), Regular, 9)
+	}
+	if c := e.Conversion; c != nil {
+		l.space(6)
+		l.paragraph(margin, contentWide, Regular, 8, muted, "Converted from "+c.From+" to "+c.To+" at "+c.Rate.String()+", rate as of "+c.Updated.UTC().Format("2006-01-02 15:04 UTC")+".")
+	}
+
+	// Handle the terms.
+	l.title("Terms")
+	l.paragraph(margin, contentWide, Regular, 9, black, "This quote is valid until "+formatDate(q.ExpiresAt)+".")
+	if b.Terms != "" {
+		l.space(4)
+		l.paragraph(margin, contentWide, Regular, 9, black, b.Terms)
+	}
+
+	// Handle the customer response.
+	if r := q.Response; r != nil {
+		l.title("Response")
+		if r.Signature != nil {
+			sig := q.SignatureModule()
+			sig.Properties.Label = "Accepted by"
+			if err := l.signature(sig, r.Signature); err != nil {
+				return err
+			}
+		} else {
+			verb := "Accepted"
+			if r.Status == types.QuoteDeclined {
+				verb = "Declined"
+			}
+			if r.Name != "" {
+				verb += " by " + r.Name
+			}
+			l.paragraph(margin, contentWide, Regular, 9, black, verb+" at "+r.At.UTC().Format("2006-01-02 15:04 UTC")+".")
+		}
+
+		return l.write(w, b.Name, "Quote "+q.Number)
+	}
+
+	// Handle the signature.
+	l.ensure(90)
+	l.space(50)
+	half := contentWide / 2
+	l.d.Line(margin, l.y, margin+half-20, l.y, 0.5, black)
+	l.d.Line(margin+half+20, l.y, PageWidth-margin, l.y, 0.5, black)
+	l.space(12)
+	l.d.Text(margin, l.y, Regular, 8, muted, "Accepted by (name and signature)")
+	l.d.Text(margin+half+20, l.y, Regular, 8, muted, "Date")
+
+	return l.write(w, b.Name, "Quote "+q.Number)
+}
+
+// formatUnitPrice formats a unit price as money, unless it has more decimal
+// places than the currency, in which case it is shown exactly.
+func formatUnitPrice(d types.Decimal, currency, locale string) string {
+	m, err := types.NewMoney(d, currency, types.RoundHalfUp)
+	if err != nil {
+		return d.String() + " " + currency
+	}
+	if md, err := m.Decimal(); err != nil || md != d {
+		return d.String() + " " + currency
+	}
+
+	return formatMoney(m, locale)
+}
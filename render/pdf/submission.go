@@ -0,0 +1,33 @@
+package pdf
+
+import (
+	"io"
+
+	"estimator/types"
+)
+
+// Submission renders a summary of the submission of the given form as a
+// PDF document, with its answers and signatures.
+func Submission(w io.Writer, f *types.Form, s *types.Submission, b *Branding) error {
+	l := newLayout("Submission " + s.ID)
+
+	// Handle the header.
+	details := [][2]string{
+		{"Reference", s.ID},
+		{"Submitted", s.Created.UTC().Format("2006-01-02 15:04 UTC")},
+	}
+	if s.Coupon != "" {
+		details = append(details, [2]string{"Coupon", s.Coupon})
+	}
+	if err := l.header(b, "Submission", details, f.Modules); err != nil {
+		return err
+	}
+
+	// Handle the answers.
+	l.title("Answers")
+	if err := l.answers(f.Modules, s.Answers, types.DateLocations(f.Modules), true); err != nil {
+		return err
+	}
+
+	return l.write(w, b.Name, "Submission "+s.ID)
+}
@@ -0,0 +1,152 @@
+package asset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"io"
+	"net/http"
+	"time"
+
+	// Register the image formats assets can be decoded from.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"estimator/storage"
+	"estimator/storage/blob"
+	"estimator/types"
+	"estimator/utils"
+)
+
+const (
+	// MaxSize defines the maximum asset size in bytes.
+	MaxSize = 5 << 20
+
+	// MaxDimension defines the maximum asset width and height in pixels.
+	MaxDimension = 4096
+)
+
+// ContentTypes defines the allowed asset content types.
+var ContentTypes []string = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+}
+
+// Service defines the asset service.
+type Service struct {
+	s *storage.Storage
+}
+
+// New creates a new service.
+func New(s *storage.Storage) *Service {
+	return &Service{
+		s: s,
+	}
+}
+
+// Upload validates and stores a new image asset. Uploading an image that is
+// already stored returns the existing asset.
+func (s *Service) Upload(r io.Reader) (*types.Asset, error) {
+	// Read the image, at most one byte past the maximum size so oversized
+	// images can be detected.
+	data, err := io.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("image is empty")
+	}
+	if len(data) > MaxSize {
+		return nil, errors.New("image is too large")
+	}
+
+	// Check the content type.
+	contentType := http.DetectContentType(data)
+	if !utils.SliceContains(ContentTypes, contentType) {
+		return nil, errors.New("image type is not allowed")
+	}
+
+	// Check the dimensions.
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("image could not be decoded")
+	}
+	if cfg.Width < 1 || cfg.Height < 1 || cfg.Width > MaxDimension || cfg.Height > MaxDimension {
+		return nil, errors.New("image dimensions are not allowed")
+	}
+
+	// Create a new Asset addressed by its content.
+	sum := sha256.Sum256(data)
+	a := &types.Asset{
+		ID:          hex.EncodeToString(sum[:]),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+	}
+	a.URL = types.AssetURL(a.ID)
+
+	// Store the image unless it already is.
+	_, err = s.s.Blob.Stat(a.ID)
+	switch {
+	case err == blob.ErrBlobNotFound:
+		if _, err := s.s.Blob.Put(&blob.Blob{
+			ID:          a.ID,
+			ContentType: a.ContentType,
+			Created:     time.Now().UTC(),
+		}, bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// GetByID gets an asset and its data by the given ID. The caller must close
+// the returned reader.
+func (s *Service) GetByID(id string) (*types.Asset, io.ReadCloser, error) {
+	// Anything that is not a content hash can not exist.
+	if !types.ValidAssetID(id) {
+		return nil, nil, blob.ErrBlobNotFound
+	}
+
+	// Get the blob from the store.
+	b, rc, err := s.s.Blob.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create a new Asset.
+	a := &types.Asset{
+		ID:          b.ID,
+		URL:         types.AssetURL(b.ID),
+		ContentType: b.ContentType,
+		Size:        b.Size,
+	}
+
+	return a, rc, nil
+}
+
+// Image gets and decodes the image of the asset with the given ID.
+func (s *Service) Image(id string) (image.Image, error) {
+	// Get the asset data.
+	_, rc, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	// Decode the image.
+	img, _, err := image.Decode(rc)
+	if err != nil {
+		return nil, errors.New("image could not be decoded")
+	}
+
+	return img, nil
+}
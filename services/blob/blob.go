@@ -0,0 +1,156 @@
+package blob
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"estimator/services/form"
+	"estimator/storage"
+	"estimator/storage/blob"
+	"estimator/types"
+
+	"github.com/google/uuid"
+)
+
+// sniffLen defines the number of bytes used to detect the content type.
+const sniffLen = 512
+
+// Service defines the blob service.
+type Service struct {
+	s    *storage.Storage
+	form *form.Service
+}
+
+// New creates a new service.
+func New(s *storage.Storage, f *form.Service) *Service {
+	return &Service{
+		s:    s,
+		form: f,
+	}
+}
+
+// GetFileUploadModule gets the file upload module with the given name from
+// the form with the given ID.
+func (s *Service) GetFileUploadModule(formID, name string) (*types.FileUpload, error) {
+	// Get the published form.
+	f, err := s.form.GetPublished(formID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the module, including nested ones.
+	for _, module := range types.AllModules(f.Modules) {
+		if module.GetName() != name {
+			continue
+		}
+
+		fu, ok := module.(*types.FileUpload)
+		if !ok {
+			return nil, errors.New("module is not a file upload module")
+		}
+
+		return fu, nil
+	}
+
+	return nil, errors.New("module could not be found")
+}
+
+// Upload streams a file for the given file upload module to the blob store.
+// The content type is detected from the file data rather than trusted from
+// the client.
+func (s *Service) Upload(m *types.FileUpload, filename string, r io.Reader) (*types.Blob, error) {
+	// Read the start of the file to detect the content type.
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+	if n == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	// Check the content type.
+	contentType := http.DetectContentType(head)
+	if !m.AllowsType(contentType) {
+		return nil, errors.New("file type is not allowed")
+	}
+
+	// Map to storage type.
+	sb := &blob.Blob{
+		ID:          uuid.NewString(),
+		ContentType: contentType,
+		Filename:    filename,
+		Created:     time.Now().UTC(),
+	}
+
+	// Stream to the blob store, reading at most one byte past the maximum
+	// size so oversized files can be detected.
+	data := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), int64(m.Properties.MaxSize)+1)
+	sb, err = s.s.Blob.Put(sb, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the size.
+	if sb.Size > int64(m.Properties.MaxSize) {
+		if err := s.s.Blob.Delete(sb.ID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("file is too large")
+	}
+
+	return storageToBlob(sb), nil
+}
+
+// Delete deletes the blob with the given ID.
+func (s *Service) Delete(id string) error {
+	return s.s.Blob.Delete(id)
+}
+
+// GetByID gets a blob and its data by the given ID. The caller must close
+// the returned reader.
+func (s *Service) GetByID(id string) (*types.Blob, io.ReadCloser, error) {
+	// Blob IDs are always UUIDs, so anything else can not exist.
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil, blob.ErrBlobNotFound
+	}
+
+	// Get the blob from the store.
+	sb, rc, err := s.s.Blob.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return storageToBlob(sb), rc, nil
+}
+
+// StatByID gets a blob by the given ID without its data.
+func (s *Service) StatByID(id string) (*types.Blob, error) {
+	// Blob IDs are always UUIDs, so anything else can not exist.
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, blob.ErrBlobNotFound
+	}
+
+	// Get the blob from the store.
+	sb, err := s.s.Blob.Stat(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return storageToBlob(sb), nil
+}
+
+// storageToBlob maps a storage blob to a blob.
+func storageToBlob(sb *blob.Blob) *types.Blob {
+	return &types.Blob{
+		ID:          sb.ID,
+		ContentType: sb.ContentType,
+		Filename:    sb.Filename,
+		Size:        sb.Size,
+		Created:     sb.Created,
+	}
+}
@@ -0,0 +1,165 @@
+package catalog
+
+import (
+	"errors"
+
+	"estimator/storage"
+	"estimator/storage/blob"
+	"estimator/storage/catalog"
+	"estimator/types"
+
+	"github.com/google/uuid"
+)
+
+// Service defines the catalog service.
+type Service struct {
+	s *storage.Storage
+}
+
+// New creates a new service.
+func New(s *storage.Storage) *Service {
+	return &Service{
+		s: s,
+	}
+}
+
+// Create creates a new catalog item.
+func (s *Service) Create(i *types.CatalogItem) (*types.CatalogItem, error) {
+	// Validate the item.
+	if err := s.validate(i); err != nil {
+		return nil, err
+	}
+
+	// Set ID.
+	i.ID = uuid.NewString()
+
+	// Create in storage.
+	if _, err := s.s.Catalog.Create(itemToStorage(i)); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Get gets a page of catalog items.
+func (s *Service) Get(limit, offset int) ([]*types.CatalogItem, error) {
+	// Get the items from storage.
+	sis, err := s.s.Catalog.Get(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map to catalog items.
+	items := []*types.CatalogItem{}
+	for _, si := range sis {
+		i, err := storageToItem(si)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, nil
+}
+
+// GetByID gets a catalog item by the given ID.
+func (s *Service) GetByID(id string) (*types.CatalogItem, error) {
+	// Get the item from storage.
+	si, err := s.s.Catalog.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return storageToItem(si)
+}
+
+// UpdateByID updates a catalog item by the given ID.
+func (s *Service) UpdateByID(id string, i *types.CatalogItem) (*types.CatalogItem, error) {
+	// Validate the item.
+	if err := s.validate(i); err != nil {
+		return nil, err
+	}
+
+	// Update in storage.
+	si, err := s.s.Catalog.UpdateByID(id, itemToStorage(i))
+	if err != nil {
+		return nil, err
+	}
+
+	return storageToItem(si)
+}
+
+// DeleteByID deletes a catalog item by the given ID.
+func (s *Service) DeleteByID(id string) error {
+	return s.s.Catalog.DeleteByID(id)
+}
+
+// validate validates a catalog item and checks its image exists.
+func (s *Service) validate(i *types.CatalogItem) error {
+	// Validate the item.
+	if err := i.Validate(); err != nil {
+		return err
+	}
+
+	// Check the image.
+	if i.Image == "" {
+		return nil
+	}
+	_, err := s.s.Blob.Stat(i.Image)
+	switch {
+	case err == blob.ErrBlobNotFound:
+		return errors.New("image could not be found")
+	case err != nil:
+		return err
+	}
+
+	return nil
+}
+
+// itemToStorage maps a catalog item to a storage catalog item.
+func itemToStorage(i *types.CatalogItem) *catalog.Item {
+	return &catalog.Item{
+		ID:          i.ID,
+		Name:        i.Name,
+		SKU:         i.SKU,
+		Description: i.Description,
+		UnitPrice:   i.UnitPrice.String(),
+		MinPrice:    i.MinPrice.String(),
+		MaxPrice:    i.MaxPrice.String(),
+		Unit:        i.Unit,
+		Image:       i.Image,
+		MinQuantity: i.MinQuantity,
+		MaxQuantity: i.MaxQuantity,
+	}
+}
+
+// storageToItem maps a storage catalog item to a catalog item.
+func storageToItem(si *catalog.Item) (*types.CatalogItem, error) {
+	// Parse the prices.
+	unitPrice, err := types.ParseDecimal(si.UnitPrice)
+	if err != nil {
+		return nil, err
+	}
+	minPrice, err := types.ParseDecimal(si.MinPrice)
+	if err != nil {
+		return nil, err
+	}
+	maxPrice, err := types.ParseDecimal(si.MaxPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.CatalogItem{
+		ID:          si.ID,
+		Name:        si.Name,
+		SKU:         si.SKU,
+		Description: si.Description,
+		UnitPrice:   unitPrice,
+		MinPrice:    minPrice,
+		MaxPrice:    maxPrice,
+		Unit:        si.Unit,
+		Image:       si.Image,
+		MinQuantity: si.MinQuantity,
+		MaxQuantity: si.MaxQuantity,
+	}, nil
+}
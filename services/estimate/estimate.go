@@ -0,0 +1,761 @@
+package estimate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	catalogsvc "estimator/services/catalog"
+	"estimator/services/exchangerate"
+	"estimator/services/form"
+	"estimator/storage"
+	"estimator/storage/catalog"
+	"estimator/storage/coupon"
+	"estimator/types"
+	"estimator/utils/formula"
+)
+
+// Service defines the estimate service.
+type Service struct {
+	s            *storage.Storage
+	form         *form.Service
+	catalog      *catalogsvc.Service
+	exchangeRate *exchangerate.Service
+}
+
+// New creates a new service.
+func New(s *storage.Storage, f *form.Service, c *catalogsvc.Service, x *exchangerate.Service) *Service {
+	return &Service{
+		s:            s,
+		form:         f,
+		catalog:      c,
+		exchangeRate: x,
+	}
+}
+
+// Estimate computes the estimate for the form with the given ID, applying
+// the coupon with the given code if not empty. When currency is not empty
+// and not the form currency, the estimate is converted to it.
+func (s *Service) Estimate(formID string, answers map[string]interface{}, code, currency string) (*types.Estimate, error) {
+	// Get the published form.
+	f, err := s.form.GetPublished(formID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.EstimateForm(f, answers, code, currency)
+}
+
+// EstimateForm computes the estimate for the given form, applying the
+// coupon with the given code if not empty, and converting it to the given
+// currency if not empty.
+func (s *Service) EstimateForm(f *types.Form, answers map[string]interface{}, code, currency string) (*types.Estimate, error) {
+	// Compute the estimate.
+	e, err := s.Calculate(f, answers, code)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" || currency == e.Currency {
+		return e, nil
+	}
+
+	return s.Convert(f, e, currency)
+}
+
+// Convert converts the estimate to the given currency, which must be one of
+// the form display currencies, at the current exchange rate. Each line is
+// converted and rounded separately, and the totals are recalculated from
+// the converted lines, so they always add up.
+func (s *Service) Convert(f *types.Form, e *types.Estimate, currency string) (*types.Estimate, error) {
+	// Check the currency is allowed.
+	p := f.Pricing
+	if p == nil {
+		p = &types.Pricing{}
+	}
+	if !p.AllowsCurrency(currency) {
+		return nil, types.ErrCurrencyNotAllowed
+	}
+
+	// Get the exchange rate.
+	r, err := s.exchangeRate.Rate(e.Currency, currency)
+	if err != nil {
+		return nil, err
+	}
+	mode := p.GetRounding()
+	convert := func(m types.Money) (types.Money, error) {
+		return m.Convert(r.Rate, currency, mode)
+	}
+
+	ce := &types.Estimate{
+		FormID:   e.FormID,
+		Lines:    []types.EstimateLine{},
+		Coupon:   e.Coupon,
+		Currency: currency,
+		Locale:   e.Locale,
+		Conversion: &types.Conversion{
+			From:    e.Currency,
+			To:      currency,
+			Rate:    r.Rate,
+			Updated: r.Updated,
+		},
+	}
+
+	// Convert the lines, totalling them as they are.
+	zero := types.Money{Currency: currency}
+	subtotal, tax, total := zero, zero, types.ExactPriceRange(zero)
+	for _, v := range e.Lines {
+		converted, err := v.Range().Map(convert)
+		if err != nil {
+			return nil, err
+		}
+		v.SetRange(converted)
+		if v.Kind != types.LineTax {
+			if v.UnitPrice, err = v.UnitPrice.Mul(r.Rate); err != nil {
+				return nil, err
+			}
+		}
+		ce.Lines = append(ce.Lines, v)
+
+		switch v.Kind {
+		case types.LineItem:
+			subtotal, err = subtotal.Add(v.Amount)
+		case types.LineTax:
+			tax, err = tax.Add(v.Amount)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !v.Included {
+			if total, err = total.Add(v.Range()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	ce.Subtotal, ce.Tax = subtotal, tax
+	ce.Total, ce.Low, ce.High = total.Expected, total.Low, total.High
+
+	return ce, nil
+}
+
+// Calculate computes the estimate for the given form and answers. Only the
+// answers given are validated, so a partially completed form can be
+// estimated as the customer goes. The form pricing settings are applied to
+// the subtotal as separate lines. Amounts are in the form currency, rounded
+// once per line with the form rounding mode.
+func (s *Service) Calculate(f *types.Form, answers map[string]interface{}, code string) (*types.Estimate, error) {
+	// Get the pricing settings.
+	p := f.Pricing
+	if p == nil {
+		p = &types.Pricing{}
+	}
+
+	e := &types.Estimate{
+		FormID:   f.ID,
+		Currency: p.GetCurrency(),
+		Locale:   p.GetLocale(),
+	}
+
+	// Get the lines.
+	lines, err := s.lines(p, f.Modules, answers)
+	if err != nil {
+		return nil, err
+	}
+	fl, err := formulaLines(p, f.Modules, answers)
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, fl...)
+	for i := range lines {
+		lines[i].Kind = types.LineItem
+	}
+	e.Lines = lines
+
+	// Total the lines.
+	subtotal := types.ExactPriceRange(types.Money{Currency: e.Currency})
+	for _, v := range e.Lines {
+		if subtotal, err = subtotal.Add(v.Range()); err != nil {
+			return nil, err
+		}
+	}
+	e.Subtotal = subtotal.Expected
+
+	// Get the coupon.
+	var c *types.Coupon
+	if code != "" {
+		c, err = s.coupon(f, code)
+		if err != nil {
+			return nil, err
+		}
+		e.Coupon = c.Code
+	}
+
+	// Apply the pricing settings.
+	total, err := applyPricing(e, p, answers, types.DateLocations(f.Modules), c, subtotal)
+	if err != nil {
+		return nil, err
+	}
+	e.Total, e.Low, e.High = total.Expected, total.Low, total.High
+
+	return e, nil
+}
+
+// coupon gets the coupon with the given code, checking it is valid now and
+// has uses left.
+func (s *Service) coupon(f *types.Form, code string) (*types.Coupon, error) {
+	// Get the coupon.
+	if f.Pricing == nil {
+		return nil, types.ErrCouponNotFound
+	}
+	c, err := f.Pricing.Coupon(code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the usage limit.
+	if c.MaxUses == 0 {
+		return c, nil
+	}
+	uses, err := s.s.Coupon.GetUses(f.ID, strings.ToUpper(c.Code))
+	if err != nil {
+		return nil, err
+	}
+	if uses >= c.MaxUses {
+		return nil, coupon.ErrUsageLimitReached
+	}
+
+	return c, nil
+}
+
+// applyPricing applies the given pricing settings to the estimate subtotal,
+// returning the total. Fees are added first, then discounts and the coupon,
+// which can't take the total below zero, then the minimum charge, and
+// finally taxes. Each is calculated for the low, expected and high amounts.
+// Conditions on dates are evaluated in the given time zones.
+func applyPricing(e *types.Estimate, p *types.Pricing, answers map[string]interface{}, locations types.Locations, c *types.Coupon, subtotal types.PriceRange) (types.PriceRange, error) {
+	currency, mode := p.GetCurrency(), p.GetRounding()
+	total := subtotal
+
+	// Handle fees.
+	for _, v := range p.Fees {
+		if v.Condition != nil && !v.Condition.Evaluate(answers, locations) {
+			continue
+		}
+		amount, err := adjustmentAmount(v.Type, v.Value, subtotal, mode)
+		if err != nil {
+			return types.PriceRange{}, err
+		}
+		if amount.IsZero() {
+			continue
+		}
+		line := types.EstimateLine{
+			Kind:        types.LineFee,
+			Description: v.Name,
+		}
+		line.SetRange(amount)
+		e.Lines = append(e.Lines, line)
+		if total, err = total.Add(amount); err != nil {
+			return types.PriceRange{}, err
+		}
+	}
+
+	// Handle discounts.
+	for _, v := range p.Discounts {
+		if v.Condition != nil && !v.Condition.Evaluate(answers, locations) {
+			continue
+		}
+		amount, err := adjustmentAmount(v.Type, v.Value, subtotal, mode)
+		if err != nil {
+			return types.PriceRange{}, err
+		}
+		if total, err = deduct(e, types.LineDiscount, v.Name, capAmount(amount, total), total); err != nil {
+			return types.PriceRange{}, err
+		}
+	}
+
+	// Handle the coupon.
+	if c != nil {
+		amount, err := adjustmentAmount(c.Type, c.Value, subtotal, mode)
+		if err != nil {
+			return types.PriceRange{}, err
+		}
+		description := c.Name
+		if description == "" {
+			description = "Coupon " + c.Code
+		}
+		if total, err = deduct(e, types.LineCoupon, description, capAmount(amount, total), total); err != nil {
+			return types.PriceRange{}, err
+		}
+	}
+
+	// Handle the minimum charge, once there is something to charge for.
+	if len(e.Lines) > 0 {
+		minimum, err := types.NewMoney(p.MinimumCharge, currency, mode)
+		if err != nil {
+			return types.PriceRange{}, err
+		}
+		amount, err := total.Map(func(v types.Money) (types.Money, error) {
+			m, err := minimum.Sub(v)
+			return m.Max(types.Money{Currency: currency}), err
+		})
+		if err != nil {
+			return types.PriceRange{}, err
+		}
+		if !amount.IsZero() {
+			line := types.EstimateLine{
+				Kind:        types.LineMinimum,
+				Description: "Minimum charge",
+			}
+			line.SetRange(amount)
+			e.Lines = append(e.Lines, line)
+			if total, err = total.Add(amount); err != nil {
+				return types.PriceRange{}, err
+			}
+		}
+	}
+
+	// Handle taxes, each on all of the lines before taxes.
+	taxable, base := e.Lines, total
+	tax := types.ExactPriceRange(types.Money{Currency: currency})
+	for _, v := range p.Taxes {
+		var amount types.PriceRange
+		var err error
+		if v.PerLine {
+			amount = types.ExactPriceRange(types.Money{Currency: currency})
+			for _, line := range taxable {
+				lineTax, err := taxAmount(v, line.Range(), mode)
+				if err != nil {
+					return types.PriceRange{}, err
+				}
+				if amount, err = amount.Add(lineTax); err != nil {
+					return types.PriceRange{}, err
+				}
+			}
+		} else if amount, err = taxAmount(v, base, mode); err != nil {
+			return types.PriceRange{}, err
+		}
+
+		line := types.EstimateLine{
+			Kind:        types.LineTax,
+			Description: v.Name,
+			UnitPrice:   v.Rate,
+			Included:    v.Inclusive,
+		}
+		line.SetRange(amount)
+		e.Lines = append(e.Lines, line)
+		if tax, err = tax.Add(amount); err != nil {
+			return types.PriceRange{}, err
+		}
+		if !v.Inclusive {
+			if total, err = total.Add(amount); err != nil {
+				return types.PriceRange{}, err
+			}
+		}
+	}
+	e.Tax = tax.Expected
+
+	return total, nil
+}
+
+// deduct adds a line of the given kind deducting the amount, unless it is
+// zero, returning the total less the amount.
+func deduct(e *types.Estimate, kind, description string, amount, total types.PriceRange) (types.PriceRange, error) {
+	if amount.IsZero() {
+		return total, nil
+	}
+
+	// Add the line.
+	negated, err := amount.Map(types.Money.Neg)
+	if err != nil {
+		return types.PriceRange{}, err
+	}
+	line := types.EstimateLine{
+		Kind:        kind,
+		Description: description,
+	}
+	line.SetRange(negated)
+	e.Lines = append(e.Lines, line)
+
+	return total.Sub(amount)
+}
+
+// adjustmentAmount gets the amount of a percentage or fixed adjustment of
+// the given subtotal.
+func adjustmentAmount(t string, value types.Decimal, subtotal types.PriceRange, mode string) (types.PriceRange, error) {
+	if t == "percentage" {
+		return subtotal.Map(func(v types.Money) (types.Money, error) {
+			return v.MulFrac(value, types.DecimalFromInt(100), mode)
+		})
+	}
+
+	m, err := types.NewMoney(value, subtotal.Expected.Currency, mode)
+	if err != nil {
+		return types.PriceRange{}, err
+	}
+
+	return types.ExactPriceRange(m), nil
+}
+
+// capAmount caps each of the amounts of a discount to the total, so the
+// total can't go below zero.
+func capAmount(amount, total types.PriceRange) types.PriceRange {
+	zero := types.Money{Currency: total.Expected.Currency}
+	return types.PriceRange{
+		Low:      amount.Low.Min(total.Low).Max(zero),
+		Expected: amount.Expected.Min(total.Expected).Max(zero),
+		High:     amount.High.Min(total.High).Max(zero),
+	}
+}
+
+// taxAmount gets the tax on the given amount. Inclusive taxes are taken out
+// of the amount rather than added to it, as amount * rate / (100 + rate).
+func taxAmount(t types.Tax, amount types.PriceRange, mode string) (types.PriceRange, error) {
+	den := types.DecimalFromInt(100)
+	if t.Inclusive {
+		den += t.Rate
+	}
+
+	return amount.Map(func(v types.Money) (types.Money, error) {
+		return v.MulFrac(t.Rate, den, mode)
+	})
+}
+
+// lines gets the lines for the answers to the given modules, skipping those
+// in hidden sections.
+func (s *Service) lines(p *types.Pricing, modules []types.Module, answers map[string]interface{}) ([]types.EstimateLine, error) {
+	lines := []types.EstimateLine{}
+
+	// Loop through the modules.
+	for _, module := range types.VisibleModules(modules, answers) {
+		// Get the answer.
+		answer, ok := answers[module.GetName()]
+		if !ok || answer == nil {
+			continue
+		}
+
+		// Validate the answer.
+		if av, ok := module.(types.AnswerValidator); ok {
+			if err := av.ValidateAnswer(answer); err != nil {
+				return nil, err
+			}
+		}
+
+		// Get the lines for the module.
+		var ml []types.EstimateLine
+		var err error
+		switch m := module.(type) {
+		case *types.MultipleChoice:
+			ml, err = optionLines(p, m, answer)
+		case *types.ProductList:
+			ml, err = s.productListLines(p, m, answer)
+		case *types.Matrix:
+			ml, err = matrixLines(p, m, answer)
+		case *types.Repeater:
+			ml, err = s.repeaterLines(p, m, answer)
+		case *types.Slider:
+			ml, err = numberLines(p, m.Name, m.Properties.Label, answer, m.Properties.Price, m.Properties.MinPrice, m.Properties.MaxPrice)
+		case *types.Rating:
+			ml, err = numberLines(p, m.Name, m.Properties.Label, answer, m.Properties.Price, m.Properties.MinPrice, m.Properties.MaxPrice)
+		case *types.Date:
+			ml, err = rushLines(p, m.Name, m.Properties.Label, m.RushFee(answer))
+		case *types.DateRange:
+			ml, err = rushLines(p, m.Name, m.Properties.Label, m.RushFee(answer))
+		}
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, ml...)
+	}
+
+	return lines, nil
+}
+
+// formulaLines gets the lines for the pricing formulas, worked out over the
+// answers to the modules that are not in hidden sections.
+func formulaLines(p *types.Pricing, modules []types.Module, answers map[string]interface{}) ([]types.EstimateLine, error) {
+	lines := []types.EstimateLine{}
+
+	// Get the visible answers.
+	visible := map[string]interface{}{}
+	for _, module := range types.VisibleModules(modules, answers) {
+		if answer, ok := answers[module.GetName()]; ok {
+			visible[module.GetName()] = answer
+		}
+	}
+
+	// Loop through the formulas.
+	for _, v := range p.Formulas {
+		// Work out the quantity.
+		f, err := formula.Parse(v.Formula)
+		if err != nil {
+			return nil, fmt.Errorf("formula %s %v", v.Name, err)
+		}
+		q, err := f.Eval(visible)
+		if err != nil {
+			return nil, fmt.Errorf("formula %s %v", v.Name, err)
+		}
+		if q < 0 {
+			return nil, fmt.Errorf("formula %s must not be negative", v.Name)
+		}
+		if q == 0 {
+			continue
+		}
+		qd, err := types.DecimalFromFloat(q)
+		if err != nil {
+			return nil, fmt.Errorf("formula %s %v", v.Name, err)
+		}
+
+		line := types.EstimateLine{
+			Description: v.Name,
+			Quantity:    q,
+			UnitPrice:   v.Price,
+		}
+		pr, err := types.NewPriceRange(v.Price, v.MinPrice, v.MaxPrice, qd, p.GetCurrency(), p.GetRounding())
+		if err != nil {
+			return nil, fmt.Errorf("formula %s %v", v.Name, err)
+		}
+		line.SetRange(pr)
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// optionLines gets the lines for the priced options selected for a multiple
+// choice module.
+func optionLines(p *types.Pricing, mc *types.MultipleChoice, answer interface{}) ([]types.EstimateLine, error) {
+	lines := []types.EstimateLine{}
+
+	// Loop through the selected options.
+	for _, v := range mc.Selected(answer) {
+		if v.Price == 0 && v.MaxPrice == 0 {
+			continue
+		}
+
+		line := types.EstimateLine{
+			Module:      mc.Name,
+			Description: mc.Properties.Label + ": " + v.Value,
+			Quantity:    1,
+			UnitPrice:   v.Price,
+		}
+		pr, err := types.NewPriceRange(v.Price, v.MinPrice, v.MaxPrice, types.DecimalFromInt(1), p.GetCurrency(), p.GetRounding())
+		if err != nil {
+			return nil, fmt.Errorf("%s %v", mc.Name, err)
+		}
+		line.SetRange(pr)
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// productListLines gets the lines for the selected catalog items, using the
+// current catalog prices.
+func (s *Service) productListLines(p *types.Pricing, pl *types.ProductList, answer interface{}) ([]types.EstimateLine, error) {
+	lines := []types.EstimateLine{}
+	quantities := pl.Quantities(answer)
+
+	// Loop through the items in the module order.
+	for _, id := range pl.Properties.Items {
+		q, ok := quantities[id]
+		if !ok {
+			continue
+		}
+
+		// Get the catalog item.
+		i, err := s.catalog.GetByID(id)
+		switch {
+		case err == catalog.ErrItemNotFound:
+			return nil, fmt.Errorf("%s contains an item that could not be found", pl.Name)
+		case err != nil:
+			return nil, err
+		}
+
+		// Check the limits.
+		if err := i.CheckQuantity(q); err != nil {
+			return nil, fmt.Errorf("%s[%s] %v", pl.Name, id, err)
+		}
+
+		line := types.EstimateLine{
+			Module:      pl.Name,
+			Description: i.Name,
+			SKU:         i.SKU,
+			Quantity:    float64(q),
+			Unit:        i.Unit,
+			UnitPrice:   i.UnitPrice,
+		}
+		pr, err := types.NewPriceRange(i.UnitPrice, i.MinPrice, i.MaxPrice, types.DecimalFromInt(int64(q)), p.GetCurrency(), p.GetRounding())
+		if err != nil {
+			return nil, fmt.Errorf("%s[%s] %v", pl.Name, id, err)
+		}
+		line.SetRange(pr)
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// matrixLines gets the lines for the priced rows of a matrix. The row price
+// is charged per selected cell, or per unit entered in number cells.
+func matrixLines(p *types.Pricing, mx *types.Matrix, answer interface{}) ([]types.EstimateLine, error) {
+	lines := []types.EstimateLine{}
+	rows, _ := answer.(map[string]interface{})
+
+	// Loop through the rows.
+	for _, row := range mx.Properties.Rows {
+		v, ok := rows[row.ID]
+		if !ok || (row.Price == 0 && row.MaxPrice == 0) {
+			continue
+		}
+
+		// Get the row quantity.
+		var q float64
+		switch cell := v.(type) {
+		case string:
+			q = 1
+		case []interface{}:
+			q = float64(len(cell))
+		case map[string]interface{}:
+			for _, n := range cell {
+				f, _ := n.(float64)
+				q += f
+			}
+		}
+		if q == 0 {
+			continue
+		}
+		qd, err := types.DecimalFromFloat(q)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%s] %v", mx.Name, row.ID, err)
+		}
+
+		line := types.EstimateLine{
+			Module:      mx.Name,
+			Description: mx.Properties.Label + ": " + row.Label,
+			Quantity:    q,
+			UnitPrice:   row.Price,
+		}
+		pr, err := types.NewPriceRange(row.Price, row.MinPrice, row.MaxPrice, qd, p.GetCurrency(), p.GetRounding())
+		if err != nil {
+			return nil, fmt.Errorf("%s[%s] %v", mx.Name, row.ID, err)
+		}
+		line.SetRange(pr)
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// numberLines gets the line for the numeric answer to a slider or rating
+// module, charging the price per unit of the answer, if priced.
+func numberLines(p *types.Pricing, name, label string, answer interface{}, price, min, max types.Decimal) ([]types.EstimateLine, error) {
+	q, _ := answer.(float64)
+	if q == 0 || (price == 0 && max == 0) {
+		return nil, nil
+	}
+	qd, err := types.DecimalFromFloat(q)
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", name, err)
+	}
+
+	line := types.EstimateLine{
+		Module:      name,
+		Description: label,
+		Quantity:    q,
+		UnitPrice:   price,
+	}
+	pr, err := types.NewPriceRange(price, min, max, qd, p.GetCurrency(), p.GetRounding())
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", name, err)
+	}
+	line.SetRange(pr)
+
+	return []types.EstimateLine{line}, nil
+}
+
+// rushLines gets the line for the rush fee of a date or date range module,
+// if any.
+func rushLines(p *types.Pricing, name, label string, fee types.Decimal) ([]types.EstimateLine, error) {
+	if fee == 0 {
+		return nil, nil
+	}
+
+	line := types.EstimateLine{
+		Module:      name,
+		Description: label + ": Rush fee",
+		Quantity:    1,
+		UnitPrice:   fee,
+	}
+	pr, err := types.NewPriceRange(fee, 0, 0, types.DecimalFromInt(1), p.GetCurrency(), p.GetRounding())
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", name, err)
+	}
+	line.SetRange(pr)
+
+	return []types.EstimateLine{line}, nil
+}
+
+// repeaterLines gets the lines for each item of a repeater, summing over the
+// items. Each item is charged the item price, multiplied by its quantity
+// module answer or quantity formula result when set, followed by the lines
+// for its child answers. Item lines are prefixed with the item label and
+// number.
+func (s *Service) repeaterLines(p *types.Pricing, r *types.Repeater, answer interface{}) ([]types.EstimateLine, error) {
+	lines := []types.EstimateLine{}
+
+	// Get the items.
+	items, err := r.Items(answer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the item label.
+	label := r.Properties.ItemLabel
+	if label == "" {
+		label = r.Properties.Label
+	}
+
+	// Loop through the items.
+	for i, item := range items {
+		prefix := fmt.Sprintf("%s %d", label, i+1)
+		module := fmt.Sprintf("%s[%d]", r.Name, i)
+
+		// Handle the item price.
+		if r.Properties.ItemPrice != 0 || r.Properties.ItemMaxPrice != 0 {
+			q, err := r.ItemQuantity(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s %v", module, err)
+			}
+			if q != 0 {
+				qd, err := types.DecimalFromFloat(q)
+				if err != nil {
+					return nil, fmt.Errorf("%s %v", module, err)
+				}
+				line := types.EstimateLine{
+					Module:      module,
+					Description: prefix,
+					Quantity:    q,
+					UnitPrice:   r.Properties.ItemPrice,
+				}
+				pr, err := types.NewPriceRange(r.Properties.ItemPrice, r.Properties.ItemMinPrice, r.Properties.ItemMaxPrice, qd, p.GetCurrency(), p.GetRounding())
+				if err != nil {
+					return nil, fmt.Errorf("%s %v", module, err)
+				}
+				line.SetRange(pr)
+				lines = append(lines, line)
+			}
+		}
+
+		// Handle the child answers.
+		il, err := s.lines(p, r.Modules, item)
+		if err != nil {
+			return nil, fmt.Errorf("%s %v", module, err)
+		}
+		for _, v := range il {
+			v.Module = module + "." + v.Module
+			v.Description = prefix + ": " + v.Description
+			lines = append(lines, v)
+		}
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,180 @@
+package exchangerate
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"estimator/storage"
+	"estimator/storage/exchangerate"
+	"estimator/types"
+)
+
+// Service defines the exchange rate service.
+type Service struct {
+	s *storage.Storage
+}
+
+// New creates a new service.
+func New(s *storage.Storage) *Service {
+	return &Service{
+		s: s,
+	}
+}
+
+// Get gets all exchange rates.
+func (s *Service) Get() ([]*types.ExchangeRate, error) {
+	// Get the rates from storage.
+	srs, err := s.s.ExchangeRate.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	// Map to exchange rates.
+	rates := []*types.ExchangeRate{}
+	for _, sr := range srs {
+		r, err := storageToRate(sr)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, r)
+	}
+
+	return rates, nil
+}
+
+// Set sets the exchange rate for its currency pair, replacing any existing
+// rate.
+func (s *Service) Set(r *types.ExchangeRate) (*types.ExchangeRate, error) {
+	// Validate the rate.
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Set the updated time.
+	r.Updated = time.Now().UTC()
+
+	// Save in storage.
+	if err := s.s.ExchangeRate.Upsert(rateToStorage(r)); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Import sets the exchange rates read from CSV, one rate per record as
+// base, quote and rate, such as "USD,CAD,1.36". A header record is skipped.
+// Every record is validated before any rate is saved, and the rates are
+// saved in a single transaction, so an invalid file changes nothing.
+func (s *Service) Import(r io.Reader) ([]*types.ExchangeRate, error) {
+	// Read the records.
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid exchange rate file, %v", err)
+	}
+
+	// Map the records to rates.
+	now := time.Now().UTC()
+	rates := []*types.ExchangeRate{}
+	for i, record := range records {
+		// Handle the header.
+		if i == 0 && strings.EqualFold(record[0], "base") {
+			continue
+		}
+
+		rate, err := types.ParseDecimal(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate on line %d, %v", i+1, err)
+		}
+		er := &types.ExchangeRate{
+			Base:    strings.ToUpper(strings.TrimSpace(record[0])),
+			Quote:   strings.ToUpper(strings.TrimSpace(record[1])),
+			Rate:    rate,
+			Updated: now,
+		}
+		if err := er.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid exchange rate on line %d, %v", i+1, err)
+		}
+		rates = append(rates, er)
+	}
+	if len(rates) == 0 {
+		return nil, errors.New("exchange rate file must not be empty")
+	}
+
+	// Save in storage.
+	srs := []*exchangerate.Rate{}
+	for _, v := range rates {
+		srs = append(srs, rateToStorage(v))
+	}
+	if err := s.s.ExchangeRate.UpsertAll(srs); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// Rate gets the exchange rate to convert from one currency to another. When
+// only the reverse rate is set, its inverse is used.
+func (s *Service) Rate(from, to string) (*types.ExchangeRate, error) {
+	// Get the rate from storage.
+	sr, err := s.s.ExchangeRate.GetByPair(from, to)
+	if err == nil {
+		return storageToRate(sr)
+	}
+	if err != exchangerate.ErrRateNotFound {
+		return nil, err
+	}
+
+	// Fall back to the reverse rate.
+	sr, err = s.s.ExchangeRate.GetByPair(to, from)
+	if err != nil {
+		return nil, err
+	}
+	r, err := storageToRate(sr)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := r.Rate.Inverse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ExchangeRate{
+		Base:    from,
+		Quote:   to,
+		Rate:    rate,
+		Updated: r.Updated,
+	}, nil
+}
+
+// rateToStorage maps an exchange rate to a storage exchange rate.
+func rateToStorage(r *types.ExchangeRate) *exchangerate.Rate {
+	return &exchangerate.Rate{
+		Base:    r.Base,
+		Quote:   r.Quote,
+		Rate:    r.Rate.String(),
+		Updated: r.Updated,
+	}
+}
+
+// storageToRate maps a storage exchange rate to an exchange rate.
+func storageToRate(sr *exchangerate.Rate) (*types.ExchangeRate, error) {
+	// Parse the rate.
+	rate, err := types.ParseDecimal(sr.Rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ExchangeRate{
+		Base:    sr.Base,
+		Quote:   sr.Quote,
+		Rate:    rate,
+		Updated: sr.Updated,
+	}, nil
+}
@@ -1,15 +1,35 @@
 package form
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"estimator/storage"
+	"estimator/storage/blob"
+	"estimator/storage/catalog"
 	"estimator/storage/form"
+	"estimator/storage/formrevision"
 	"estimator/types"
+	"estimator/utils/formula"
+	"estimator/utils/jsonpatch"
 
 	"github.com/google/uuid"
 )
 
+// Patch formats.
+const (
+	// PatchJSON defines the JSON Patch (RFC 6902) format.
+	PatchJSON = "json-patch"
+
+	// PatchMerge defines the JSON Merge Patch (RFC 7396) format.
+	PatchMerge = "merge-patch"
+)
+
 // Service defines the form service.
 type Service struct {
 	s *storage.Storage
@@ -22,32 +42,62 @@ func New(s *storage.Storage) *Service {
 	}
 }
 
-// Create creates a new form.
+// Create creates a new form, along with its first revision, which is
+// published straight away.
 func (s *Service) Create(f *types.Form) (*types.Form, error) {
 	var err error
 
-	// Set ID.
+	// Set ID, revision and version.
 	f.ID = uuid.NewString()
+	f.Revision = 1
+	f.PublishedRevision = 1
+	f.Version = 1
 
-	// Loop through the modules.
-	for i, module := range f.Modules {
-		// Validate the module.
+	// Validate the modules, containers validate their children.
+	for _, module := range f.Modules {
 		if err := module.Validate(); err != nil {
 			return nil, err
 		}
+	}
+	if err := types.ValidateNames(f.Modules); err != nil {
+		return nil, err
+	}
+
+	// Validate the pricing settings.
+	if f.Pricing == nil {
+		f.Pricing = &types.Pricing{}
+	}
+	if err := f.Pricing.Validate(); err != nil {
+		return nil, err
+	}
+	if err := validateFormulas(f); err != nil {
+		return nil, err
+	}
+
+	// Check the module references exist, including nested ones.
+	for _, module := range types.AllModules(f.Modules) {
+		if err := s.validateReferences(module); err != nil {
+			return nil, err
+		}
+	}
 
-		// Set ID.
-		f.Modules[i].SetID(uuid.NewString())
+	// Set the module and option IDs, none of which exist yet.
+	if err := setIDs(f.Modules, nil); err != nil {
+		return nil, err
 	}
 
 	// Map to storage type.
 	sf := &form.Form{
-		ID:      f.ID,
-		Modules: f.Modules,
+		ID:                f.ID,
+		Modules:           f.Modules,
+		Pricing:           f.Pricing,
+		Revision:          f.Revision,
+		Version:           f.Version,
+		PublishedRevision: f.PublishedRevision,
 	}
 
-	// Create in storage.
-	sf, err = s.s.Form.Create(sf)
+	// Create in storage, along with the first revision.
+	sf, err = s.s.Form.CreateWithRevision(sf, newRevision(f, ""))
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +105,7 @@ func (s *Service) Create(f *types.Form) (*types.Form, error) {
 	return f, nil
 }
 
-// GetByID gets a form by the given ID.
+// GetByID gets the working draft of the form with the given ID.
 func (s *Service) GetByID(id string) (*types.Form, error) {
 	// Try to pull this form from the database.
 	dbf, err := s.s.Form.GetByID(id)
@@ -63,48 +113,387 @@ func (s *Service) GetByID(id string) (*types.Form, error) {
 		return nil, err
 	}
 
+	return s.storageToForm(dbf)
+}
+
+// GetPublished gets the published revision of the form with the given ID,
+// which is what customers see and submit. Forms that were never published
+// are not found.
+func (s *Service) GetPublished(id string) (*types.Form, error) {
+	// Try to pull this form from the database.
+	dbf, err := s.s.Form.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if dbf.PublishedRevision == 0 {
+		return nil, form.ErrFormNotFound
+	}
+
+	// Handle forms without unpublished changes.
+	if dbf.PublishedRevision == dbf.Revision {
+		return s.storageToForm(dbf)
+	}
+
+	// Get the published revision.
+	r, err := s.GetRevision(id, dbf.PublishedRevision)
+	if err != nil {
+		return nil, err
+	}
+	f := r.Form
+	f.PublishedRevision = dbf.PublishedRevision
+	f.UnpublishedChanges = true
+	f.Version = dbf.Version
+
+	return f, nil
+}
+
+// Publish publishes the working draft of the form with the given ID. The
+// version must be the current version of the form.
+func (s *Service) Publish(id string, version int) (*types.Form, error) {
+	// Get the draft.
+	f, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if f.Version != version {
+		return nil, form.ErrVersionConflict
+	}
+
+	// Publish in storage, if the version has not changed.
+	if err := s.s.Form.Publish(id, f.Revision, version); err != nil {
+		return nil, err
+	}
+	f.PublishedRevision = f.Revision
+	f.UnpublishedChanges = false
+	f.Version++
+
+	return f, nil
+}
+
+// storageToForm maps a storage form to its working draft.
+func (s *Service) storageToForm(dbf *form.Form) (*types.Form, error) {
 	// Convert interface to modules.
-	m, err := s.InterfaceToModules(dbf.Modules.([]interface{}))
+	modules, ok := dbf.Modules.([]interface{})
+	if !ok {
+		return nil, errors.New("invalid form modules")
+	}
+	m, err := s.InterfaceToModules(modules)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to pricing settings.
+	p, err := s.InterfaceToPricing(dbf.Pricing)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create a new Form.
 	f := &types.Form{
-		ID:      dbf.ID,
-		Modules: m,
+		ID:                 dbf.ID,
+		Modules:            m,
+		Pricing:            p,
+		Revision:           dbf.Revision,
+		Version:            dbf.Version,
+		PublishedRevision:  dbf.PublishedRevision,
+		UnpublishedChanges: dbf.Revision != dbf.PublishedRevision,
 	}
 
 	return f, nil
 }
 
-// UpdateByIDAndMemberID updates a form by the given ID and member ID.
+// UpdateByIDAndMemberID updates the working draft of a form by the given ID
+// and member ID. Modules and options keep their IDs when they exist in the
+// draft, and others are given a new one. Every update creates a new revision
+// of the form, authored by the member, which customers see once it is
+// published. The version of the given form must be the current version,
+// otherwise ErrVersionConflict is returned.
 func (s *Service) UpdateByIDAndMemberID(id, memberID string, f *types.Form) (*types.Form, error) {
+	return s.update(id, memberID, f, nil)
+}
+
+// update updates the working draft of a form as UpdateByIDAndMemberID does.
+// Modules and options also keep their IDs when they exist in the given
+// modules.
+func (s *Service) update(id, memberID string, f *types.Form, known []types.Module) (*types.Form, error) {
 	var err error
 
-	// Loop through the modules.
+	// Validate the modules, containers validate their children.
 	for _, module := range f.Modules {
-		// Validate the module.
 		if err := module.Validate(); err != nil {
 			return nil, err
 		}
 	}
+	if err := types.ValidateNames(f.Modules); err != nil {
+		return nil, err
+	}
+
+	// Validate the pricing settings.
+	if f.Pricing == nil {
+		f.Pricing = &types.Pricing{}
+	}
+	if err := f.Pricing.Validate(); err != nil {
+		return nil, err
+	}
+	if err := validateFormulas(f); err != nil {
+		return nil, err
+	}
+
+	// Check the module references exist, including nested ones.
+	for _, module := range types.AllModules(f.Modules) {
+		if err := s.validateReferences(module); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get the current draft.
+	cur, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the IDs of new modules and options.
+	if err := setIDs(f.Modules, append(known, cur.Modules...)); err != nil {
+		return nil, err
+	}
+	f.ID = id
+	f.Revision = cur.Revision + 1
+	f.PublishedRevision = cur.PublishedRevision
+	f.UnpublishedChanges = true
 
 	// Map to storage type.
 	sf := &form.Form{
-		ID:      id,
-		Modules: f.Modules,
+		ID:       id,
+		Modules:  f.Modules,
+		Pricing:  f.Pricing,
+		Revision: f.Revision,
+		Version:  f.Version,
 	}
 
-	// Create in storage.
-	sf, err = s.s.Form.UpdateByID(id, sf)
+	// Update in storage along with recording the revision, if the version
+	// has not changed.
+	sf, err = s.s.Form.UpdateWithRevision(id, sf, newRevision(f, memberID))
 	if err != nil {
 		return nil, err
 	}
+	f.Version = sf.Version
 
 	return f, nil
 }
 
+// Patch updates the working draft of a form by the given ID and member ID
+// with a patch in the given format. The patch is applied to the draft as a
+// JSON object with its "modules" and "pricing", and the result is validated
+// as a full update. The version must be the current version of the form.
+func (s *Service) Patch(id, memberID string, version int, format string, patch []byte) (*types.Form, error) {
+	// Get the draft.
+	f, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if f.Version != version {
+		return nil, form.ErrVersionConflict
+	}
+
+	// Get the draft as a JSON object.
+	b, err := json.Marshal(map[string]interface{}{
+		"modules": f.Modules,
+		"pricing": f.Pricing,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	// Apply the patch.
+	switch format {
+	case PatchJSON:
+		doc, err = jsonpatch.Apply(doc, patch)
+	case PatchMerge:
+		doc, err = jsonpatch.Merge(doc, patch)
+	default:
+		return nil, errors.New("invalid patch format")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the patched object to modules and pricing settings.
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("patched form must be an object")
+	}
+	modules, ok := m["modules"].([]interface{})
+	if !ok {
+		return nil, errors.New("patched form modules must be an array")
+	}
+	pm, err := s.InterfaceToModules(modules)
+	if err != nil {
+		return nil, err
+	}
+	pp, err := s.InterfaceToPricing(m["pricing"])
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the form.
+	return s.UpdateByIDAndMemberID(id, memberID, &types.Form{
+		Modules: pm,
+		Pricing: pp,
+		Version: version,
+	})
+}
+
+// validateFormulas checks the pricing formulas of the form only reference
+// modules of the form answered alongside the others, so not those in
+// repeaters.
+func validateFormulas(f *types.Form) error {
+	// Map module names.
+	names := map[string]bool{}
+	for _, module := range types.Flatten(f.Modules) {
+		names[module.GetName()] = true
+	}
+
+	// Check the formulas.
+	for _, v := range f.Pricing.Formulas {
+		fp, err := formula.Parse(v.Formula)
+		if err != nil {
+			return err
+		}
+		for _, name := range fp.Names() {
+			if !names[name] {
+				return fmt.Errorf("invalid formula %s, %s is not the name of a module", v.Name, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setIDs gives the given modules, including nested ones, and the options of
+// each multiple choice module a new ID, unless their ID exists in the known
+// modules. The kept IDs must be unique, as must the option IDs of each
+// module.
+func setIDs(modules, known []types.Module) error {
+	// Get the known module and option IDs.
+	knownIDs := make(map[string]bool)
+	knownOptionIDs := make(map[string]map[string]bool)
+	for _, module := range types.AllModules(known) {
+		knownIDs[module.GetID()] = true
+		if mc, ok := module.(*types.MultipleChoice); ok {
+			if knownOptionIDs[mc.ID] == nil {
+				knownOptionIDs[mc.ID] = make(map[string]bool)
+			}
+			for _, option := range mc.Properties.Options {
+				knownOptionIDs[mc.ID][option.ID] = true
+			}
+		}
+	}
+
+	ids := make(map[string]bool)
+	for _, module := range types.AllModules(modules) {
+		// Handle module ID.
+		if id := module.GetID(); !knownIDs[id] {
+			module.SetID(uuid.NewString())
+		} else if ids[id] {
+			return fmt.Errorf("duplicate module ID %s", id)
+		}
+		ids[module.GetID()] = true
+
+		// Handle multiple choice option IDs.
+		mc, ok := module.(*types.MultipleChoice)
+		if !ok {
+			continue
+		}
+		optionIDs := make(map[string]bool)
+		for i := range mc.Properties.Options {
+			option := &mc.Properties.Options[i]
+			if !knownOptionIDs[mc.ID][option.ID] {
+				option.ID = uuid.NewString()
+			} else if optionIDs[option.ID] {
+				return fmt.Errorf("duplicate option ID %s in module %s", option.ID, mc.Name)
+			}
+			optionIDs[option.ID] = true
+		}
+	}
+
+	return nil
+}
+
+// newRevision returns the current definition of the given form as a
+// revision authored by the given member.
+func newRevision(f *types.Form, author string) *formrevision.Revision {
+	return &formrevision.Revision{
+		FormID:  f.ID,
+		Number:  f.Revision,
+		Author:  author,
+		Modules: f.Modules,
+		Pricing: f.Pricing,
+		Created: time.Now().UTC(),
+	}
+}
+
+// validateReferences checks that the assets and catalog items referenced by
+// the given module exist.
+func (s *Service) validateReferences(module types.Module) error {
+	switch m := module.(type) {
+	case *types.Heading:
+		// Check the image.
+		if m.Properties.Image == "" {
+			return nil
+		}
+		b, err := s.s.Blob.Stat(m.Properties.Image)
+		switch {
+		case err == blob.ErrBlobNotFound:
+			return errors.New("heading image could not be found")
+		case err != nil:
+			return err
+		}
+		if !strings.HasPrefix(b.ContentType, "image/") {
+			return errors.New("heading image is not an image")
+		}
+	case *types.ProductList:
+		// Check the catalog items.
+		for _, id := range m.Properties.Items {
+			_, err := s.s.Catalog.GetByID(id)
+			switch {
+			case err == catalog.ErrItemNotFound:
+				return fmt.Errorf("catalog item %s could not be found", id)
+			case err != nil:
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Prefill prefills the modules of the given form from the given query
+// parameters and referrer. Only parameters allow-listed by a module are used,
+// and those are returned with their values.
+func (s *Service) Prefill(f *types.Form, params url.Values, referrer string) map[string]string {
+	applied := map[string]string{}
+
+	// Loop through the modules, including nested ones.
+	for _, module := range types.Flatten(f.Modules) {
+		// Prefill the module if it supports it.
+		p, ok := module.(types.Prefiller)
+		if !ok {
+			continue
+		}
+		for _, name := range p.Prefill(params, referrer) {
+			applied[name] = params.Get(name)
+		}
+	}
+
+	return applied
+}
+
 // InterfaceToModules takes in an []interface{} and converts it to individual
 // module types.
 func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
@@ -114,7 +503,10 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 	// Loop through the interface slice.
 	for _, v := range i {
 		// Convert to map[string]interface{}.
-		m := v.(map[string]interface{})
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("invalid module, must be an object")
+		}
 
 		// Get type.
 		t, ok := m["type"]
@@ -153,7 +545,10 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 			if !ok {
 				return nil, errors.New("missing properties")
 			}
-			pm := p.(map[string]interface{})
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
 
 			properties := types.ShortTextProperties{}
 
@@ -250,7 +645,7 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 			if !ok {
 				return nil, errors.New("missing property width")
 			}
-			widthFloat64, ok := width.(float64)
+			widthFloat64, ok := interfaceToFloat64(width)
 			if !ok {
 				return nil, errors.New("invalid property width, must be an integer")
 			}
@@ -267,6 +662,16 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 			}
 			properties.Validation = validationStr
 
+			// Handle property prefill param. It is optional, as forms
+			// saved before it existed do not have it.
+			if prefillParam, ok := pm["prefill_param"]; ok {
+				prefillParamStr, ok := prefillParam.(string)
+				if !ok {
+					return nil, errors.New("invalid property prefill param, must be a string")
+				}
+				properties.PrefillParam = prefillParamStr
+			}
+
 			// Set the properties.
 			module.Properties = properties
 
@@ -300,7 +705,10 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 			if !ok {
 				return nil, errors.New("missing properties")
 			}
-			pm := p.(map[string]interface{})
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
 
 			properties := types.MultipleChoiceProperties{}
 
@@ -386,7 +794,7 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 			if !ok {
 				return nil, errors.New("missing property width")
 			}
-			widthFloat64, ok := width.(float64)
+			widthFloat64, ok := interfaceToFloat64(width)
 			if !ok {
 				return nil, errors.New("invalid property width, must be an integer")
 			}
@@ -409,16 +817,16 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 					return nil, errors.New("option is invalid")
 				}
 
-				optionID, ok := option["id"]
-				if !ok {
-					return nil, errors.New("could not get 'id' property of multiple choice option")
-				}
-				optionIDStr, ok := optionID.(string)
-				if !ok {
-					return nil, errors.New("invalid option ID, must be a string")
+				// Handle option ID, optional.
+				var optionIDStr string
+				if optionID, ok := option["id"]; ok && optionID != nil {
+					optionIDStr, ok = optionID.(string)
+					if !ok {
+						return nil, errors.New("invalid option ID, must be a string")
+					}
 				}
 
-				optionValue, ok := option["id"]
+				optionValue, ok := option["value"]
 				if !ok {
 					return nil, errors.New("could not get 'value' property of multiple choice option")
 				}
@@ -427,14 +835,56 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 					return nil, errors.New("invalid option value, must be a string")
 				}
 
+				// Option prices are optional, as they were added later.
+				var optionPriceDecimal, optionMinPriceDecimal, optionMaxPriceDecimal types.Decimal
+				if optionPrice, ok := option["price"]; ok {
+					optionPriceDecimal, ok = interfaceToDecimal(optionPrice)
+					if !ok {
+						return nil, errors.New("invalid option price, must be a decimal number")
+					}
+				}
+				if optionMinPrice, ok := option["min_price"]; ok {
+					optionMinPriceDecimal, ok = interfaceToDecimal(optionMinPrice)
+					if !ok {
+						return nil, errors.New("invalid option min price, must be a decimal number")
+					}
+				}
+				if optionMaxPrice, ok := option["max_price"]; ok {
+					optionMaxPriceDecimal, ok = interfaceToDecimal(optionMaxPrice)
+					if !ok {
+						return nil, errors.New("invalid option max price, must be a decimal number")
+					}
+				}
+
 				optionsType = append(optionsType, types.MultipleChoiceOption{
-					ID:    optionIDStr,
-					Value: optionValueStr,
+					ID:       optionIDStr,
+					Value:    optionValueStr,
+					Price:    optionPriceDecimal,
+					MinPrice: optionMinPriceDecimal,
+					MaxPrice: optionMaxPriceDecimal,
 				})
 
 			}
 			properties.Options = optionsType
 
+			// Handle property default, optional.
+			if defaultp, ok := pm["default"]; ok {
+				defaultpStr, ok := defaultp.(string)
+				if !ok {
+					return nil, errors.New("invalid property default, must be a string")
+				}
+				properties.Default = defaultpStr
+			}
+
+			// Handle property prefill param, optional.
+			if prefillParam, ok := pm["prefill_param"]; ok {
+				prefillParamStr, ok := prefillParam.(string)
+				if !ok {
+					return nil, errors.New("invalid property prefill param, must be a string")
+				}
+				properties.PrefillParam = prefillParamStr
+			}
+
 			// Set the properties.
 			module.Properties = properties
 
@@ -468,7 +918,10 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 			if !ok {
 				return nil, errors.New("missing properties")
 			}
-			pm := p.(map[string]interface{})
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
 
 			properties := types.HeadingProperties{}
 
@@ -538,14 +991,26 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 			}
 			properties.VerticalAlignment = verticalAlignmentStr
 
-			// TODO: Handle property image.
+			// Handle property image. It is optional, as forms saved
+			// before it existed do not have it.
+			if image, ok := pm["image"]; ok {
+				imageStr, ok := image.(string)
+				if !ok {
+					return nil, errors.New("invalid property image, must be a string")
+				}
+				properties.Image = imageStr
+			}
+
+			// Handle property image URL, which is derived from the
+			// image rather than taken from the input.
+			properties.ImageURL = types.AssetURL(properties.Image)
 
 			// Handle property imageWidth.
 			imageWidth, ok := pm["image_width"]
 			if !ok {
 				return nil, errors.New("missing property image width")
 			}
-			imageWidthFloat64, ok := imageWidth.(float64)
+			imageWidthFloat64, ok := interfaceToFloat64(imageWidth)
 			if !ok {
 				return nil, errors.New("invalid property image width, must be an integer")
 			}
@@ -584,7 +1049,10 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 			if !ok {
 				return nil, errors.New("missing properties")
 			}
-			pm := p.(map[string]interface{})
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
 
 			properties := types.FullNameProperties{}
 
@@ -713,10 +1181,2099 @@ func (s *Service) InterfaceToModules(i []interface{}) ([]types.Module, error) {
 			module.Properties = properties
 
 			modules = append(modules, module)
-		default:
-			return nil, errors.New("invalid module type")
-		}
-	}
+		case "date":
+			module := &types.Date{}
 
-	return modules, nil
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.DateProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property min date.
+			minDate, ok := pm["min_date"]
+			if !ok {
+				return nil, errors.New("missing property min date")
+			}
+			minDateStr, ok := minDate.(string)
+			if !ok {
+				return nil, errors.New("invalid property min date, must be a string")
+			}
+			properties.MinDate = minDateStr
+
+			// Handle property max date.
+			maxDate, ok := pm["max_date"]
+			if !ok {
+				return nil, errors.New("missing property max date")
+			}
+			maxDateStr, ok := maxDate.(string)
+			if !ok {
+				return nil, errors.New("invalid property max date, must be a string")
+			}
+			properties.MaxDate = maxDateStr
+
+			// Handle property disabled weekdays.
+			disabledWeekdays, ok := pm["disabled_weekdays"]
+			if !ok {
+				return nil, errors.New("missing property disabled weekdays")
+			}
+			disabledWeekdaysSlice, ok := disabledWeekdays.([]interface{})
+			if !ok {
+				return nil, errors.New("invalid property disabled weekdays, must be an array of integers")
+			}
+			properties.DisabledWeekdays = []int{}
+			for _, v := range disabledWeekdaysSlice {
+				weekdayFloat64, ok := interfaceToFloat64(v)
+				if !ok {
+					return nil, errors.New("invalid disabled weekday, must be an integer")
+				}
+				properties.DisabledWeekdays = append(properties.DisabledWeekdays, int(weekdayFloat64))
+			}
+
+			// Handle property blackout dates.
+			blackoutDates, ok := pm["blackout_dates"]
+			if !ok {
+				return nil, errors.New("missing property blackout dates")
+			}
+			blackoutDatesSlice, ok := blackoutDates.([]interface{})
+			if !ok {
+				return nil, errors.New("invalid property blackout dates, must be an array of strings")
+			}
+			properties.BlackoutDates = []string{}
+			for _, v := range blackoutDatesSlice {
+				blackoutDateStr, ok := v.(string)
+				if !ok {
+					return nil, errors.New("invalid blackout date, must be a string")
+				}
+				properties.BlackoutDates = append(properties.BlackoutDates, blackoutDateStr)
+			}
+
+			// Handle property format.
+			format, ok := pm["format"]
+			if !ok {
+				return nil, errors.New("missing property format")
+			}
+			formatStr, ok := format.(string)
+			if !ok {
+				return nil, errors.New("invalid property format, must be a string")
+			}
+			properties.Format = formatStr
+
+			// Handle property time zone.
+			timeZone, ok := pm["time_zone"]
+			if !ok {
+				return nil, errors.New("missing property time zone")
+			}
+			timeZoneStr, ok := timeZone.(string)
+			if !ok {
+				return nil, errors.New("invalid property time zone, must be a string")
+			}
+			properties.TimeZone = timeZoneStr
+
+			// Handle property rush days, optional.
+			if rushDays, ok := pm["rush_days"]; ok {
+				rushDaysFloat64, ok := interfaceToFloat64(rushDays)
+				if !ok || rushDaysFloat64 != float64(int(rushDaysFloat64)) {
+					return nil, errors.New("invalid property rush days, must be an integer")
+				}
+				properties.RushDays = int(rushDaysFloat64)
+			}
+
+			// Handle property rush fee, optional.
+			if rushFee, ok := pm["rush_fee"]; ok {
+				rushFeeDecimal, ok := interfaceToDecimal(rushFee)
+				if !ok {
+					return nil, errors.New("invalid property rush fee, must be a decimal number")
+				}
+				properties.RushFee = rushFeeDecimal
+			}
+
+			// Handle property default, optional.
+			if defaultp, ok := pm["default"]; ok {
+				defaultpStr, ok := defaultp.(string)
+				if !ok {
+					return nil, errors.New("invalid property default, must be a string")
+				}
+				properties.Default = defaultpStr
+			}
+
+			// Handle property prefill param, optional.
+			if prefillParam, ok := pm["prefill_param"]; ok {
+				prefillParamStr, ok := prefillParam.(string)
+				if !ok {
+					return nil, errors.New("invalid property prefill param, must be a string")
+				}
+				properties.PrefillParam = prefillParamStr
+			}
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "time":
+			module := &types.Time{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.TimeProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property min time.
+			minTime, ok := pm["min_time"]
+			if !ok {
+				return nil, errors.New("missing property min time")
+			}
+			minTimeStr, ok := minTime.(string)
+			if !ok {
+				return nil, errors.New("invalid property min time, must be a string")
+			}
+			properties.MinTime = minTimeStr
+
+			// Handle property max time.
+			maxTime, ok := pm["max_time"]
+			if !ok {
+				return nil, errors.New("missing property max time")
+			}
+			maxTimeStr, ok := maxTime.(string)
+			if !ok {
+				return nil, errors.New("invalid property max time, must be a string")
+			}
+			properties.MaxTime = maxTimeStr
+
+			// Handle property step.
+			step, ok := pm["step"]
+			if !ok {
+				return nil, errors.New("missing property step")
+			}
+			stepFloat64, ok := interfaceToFloat64(step)
+			if !ok {
+				return nil, errors.New("invalid property step, must be an integer")
+			}
+			properties.Step = int(stepFloat64)
+
+			// Handle property format.
+			format, ok := pm["format"]
+			if !ok {
+				return nil, errors.New("missing property format")
+			}
+			formatStr, ok := format.(string)
+			if !ok {
+				return nil, errors.New("invalid property format, must be a string")
+			}
+			properties.Format = formatStr
+
+			// Handle property time zone.
+			timeZone, ok := pm["time_zone"]
+			if !ok {
+				return nil, errors.New("missing property time zone")
+			}
+			timeZoneStr, ok := timeZone.(string)
+			if !ok {
+				return nil, errors.New("invalid property time zone, must be a string")
+			}
+			properties.TimeZone = timeZoneStr
+
+			// Handle property default, optional.
+			if defaultp, ok := pm["default"]; ok {
+				defaultpStr, ok := defaultp.(string)
+				if !ok {
+					return nil, errors.New("invalid property default, must be a string")
+				}
+				properties.Default = defaultpStr
+			}
+
+			// Handle property prefill param, optional.
+			if prefillParam, ok := pm["prefill_param"]; ok {
+				prefillParamStr, ok := prefillParam.(string)
+				if !ok {
+					return nil, errors.New("invalid property prefill param, must be a string")
+				}
+				properties.PrefillParam = prefillParamStr
+			}
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "date-range":
+			module := &types.DateRange{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.DateRangeProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property min date.
+			minDate, ok := pm["min_date"]
+			if !ok {
+				return nil, errors.New("missing property min date")
+			}
+			minDateStr, ok := minDate.(string)
+			if !ok {
+				return nil, errors.New("invalid property min date, must be a string")
+			}
+			properties.MinDate = minDateStr
+
+			// Handle property max date.
+			maxDate, ok := pm["max_date"]
+			if !ok {
+				return nil, errors.New("missing property max date")
+			}
+			maxDateStr, ok := maxDate.(string)
+			if !ok {
+				return nil, errors.New("invalid property max date, must be a string")
+			}
+			properties.MaxDate = maxDateStr
+
+			// Handle property min days.
+			minDays, ok := pm["min_days"]
+			if !ok {
+				return nil, errors.New("missing property min days")
+			}
+			minDaysFloat64, ok := interfaceToFloat64(minDays)
+			if !ok {
+				return nil, errors.New("invalid property min days, must be an integer")
+			}
+			properties.MinDays = int(minDaysFloat64)
+
+			// Handle property max days.
+			maxDays, ok := pm["max_days"]
+			if !ok {
+				return nil, errors.New("missing property max days")
+			}
+			maxDaysFloat64, ok := interfaceToFloat64(maxDays)
+			if !ok {
+				return nil, errors.New("invalid property max days, must be an integer")
+			}
+			properties.MaxDays = int(maxDaysFloat64)
+
+			// Handle property disabled weekdays.
+			disabledWeekdays, ok := pm["disabled_weekdays"]
+			if !ok {
+				return nil, errors.New("missing property disabled weekdays")
+			}
+			disabledWeekdaysSlice, ok := disabledWeekdays.([]interface{})
+			if !ok {
+				return nil, errors.New("invalid property disabled weekdays, must be an array of integers")
+			}
+			properties.DisabledWeekdays = []int{}
+			for _, v := range disabledWeekdaysSlice {
+				weekdayFloat64, ok := interfaceToFloat64(v)
+				if !ok {
+					return nil, errors.New("invalid disabled weekday, must be an integer")
+				}
+				properties.DisabledWeekdays = append(properties.DisabledWeekdays, int(weekdayFloat64))
+			}
+
+			// Handle property blackout dates.
+			blackoutDates, ok := pm["blackout_dates"]
+			if !ok {
+				return nil, errors.New("missing property blackout dates")
+			}
+			blackoutDatesSlice, ok := blackoutDates.([]interface{})
+			if !ok {
+				return nil, errors.New("invalid property blackout dates, must be an array of strings")
+			}
+			properties.BlackoutDates = []string{}
+			for _, v := range blackoutDatesSlice {
+				blackoutDateStr, ok := v.(string)
+				if !ok {
+					return nil, errors.New("invalid blackout date, must be a string")
+				}
+				properties.BlackoutDates = append(properties.BlackoutDates, blackoutDateStr)
+			}
+
+			// Handle property format.
+			format, ok := pm["format"]
+			if !ok {
+				return nil, errors.New("missing property format")
+			}
+			formatStr, ok := format.(string)
+			if !ok {
+				return nil, errors.New("invalid property format, must be a string")
+			}
+			properties.Format = formatStr
+
+			// Handle property time zone.
+			timeZone, ok := pm["time_zone"]
+			if !ok {
+				return nil, errors.New("missing property time zone")
+			}
+			timeZoneStr, ok := timeZone.(string)
+			if !ok {
+				return nil, errors.New("invalid property time zone, must be a string")
+			}
+			properties.TimeZone = timeZoneStr
+
+			// Handle property rush days, optional.
+			if rushDays, ok := pm["rush_days"]; ok {
+				rushDaysFloat64, ok := interfaceToFloat64(rushDays)
+				if !ok || rushDaysFloat64 != float64(int(rushDaysFloat64)) {
+					return nil, errors.New("invalid property rush days, must be an integer")
+				}
+				properties.RushDays = int(rushDaysFloat64)
+			}
+
+			// Handle property rush fee, optional.
+			if rushFee, ok := pm["rush_fee"]; ok {
+				rushFeeDecimal, ok := interfaceToDecimal(rushFee)
+				if !ok {
+					return nil, errors.New("invalid property rush fee, must be a decimal number")
+				}
+				properties.RushFee = rushFeeDecimal
+			}
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "file-upload":
+			module := &types.FileUpload{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.FileUploadProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property allowed types.
+			allowedTypes, ok := pm["allowed_types"]
+			if !ok {
+				return nil, errors.New("missing property allowed types")
+			}
+			allowedTypesSlice, ok := allowedTypes.([]interface{})
+			if !ok {
+				return nil, errors.New("invalid property allowed types, must be an array of strings")
+			}
+			properties.AllowedTypes = []string{}
+			for _, v := range allowedTypesSlice {
+				allowedTypeStr, ok := v.(string)
+				if !ok {
+					return nil, errors.New("invalid allowed type, must be a string")
+				}
+				properties.AllowedTypes = append(properties.AllowedTypes, allowedTypeStr)
+			}
+
+			// Handle property max size.
+			maxSize, ok := pm["max_size"]
+			if !ok {
+				return nil, errors.New("missing property max size")
+			}
+			maxSizeFloat64, ok := interfaceToFloat64(maxSize)
+			if !ok {
+				return nil, errors.New("invalid property max size, must be an integer")
+			}
+			properties.MaxSize = int(maxSizeFloat64)
+
+			// Handle property max count.
+			maxCount, ok := pm["max_count"]
+			if !ok {
+				return nil, errors.New("missing property max count")
+			}
+			maxCountFloat64, ok := interfaceToFloat64(maxCount)
+			if !ok {
+				return nil, errors.New("invalid property max count, must be an integer")
+			}
+			properties.MaxCount = int(maxCountFloat64)
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "slider":
+			module := &types.Slider{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.SliderProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property min.
+			min, ok := pm["min"]
+			if !ok {
+				return nil, errors.New("missing property min")
+			}
+			minFloat64, ok := interfaceToFloat64(min)
+			if !ok {
+				return nil, errors.New("invalid property min, must be a number")
+			}
+			properties.Min = minFloat64
+
+			// Handle property max.
+			max, ok := pm["max"]
+			if !ok {
+				return nil, errors.New("missing property max")
+			}
+			maxFloat64, ok := interfaceToFloat64(max)
+			if !ok {
+				return nil, errors.New("invalid property max, must be a number")
+			}
+			properties.Max = maxFloat64
+
+			// Handle property step.
+			step, ok := pm["step"]
+			if !ok {
+				return nil, errors.New("missing property step")
+			}
+			stepFloat64, ok := interfaceToFloat64(step)
+			if !ok {
+				return nil, errors.New("invalid property step, must be a number")
+			}
+			properties.Step = stepFloat64
+
+			// Handle property ticks.
+			ticks, ok := pm["ticks"]
+			if !ok {
+				return nil, errors.New("missing property ticks")
+			}
+			ticksSlice, ok := ticks.([]interface{})
+			if !ok {
+				return nil, errors.New("slider ticks is not an array of objects")
+			}
+			properties.Ticks = []types.SliderTick{}
+			for _, v := range ticksSlice {
+				tick, ok := v.(map[string]interface{})
+				if !ok {
+					return nil, errors.New("tick is invalid")
+				}
+
+				tickValue, ok := tick["value"]
+				if !ok {
+					return nil, errors.New("could not get 'value' property of slider tick")
+				}
+				tickValueFloat64, ok := interfaceToFloat64(tickValue)
+				if !ok {
+					return nil, errors.New("invalid tick value, must be a number")
+				}
+
+				tickLabel, ok := tick["label"]
+				if !ok {
+					return nil, errors.New("could not get 'label' property of slider tick")
+				}
+				tickLabelStr, ok := tickLabel.(string)
+				if !ok {
+					return nil, errors.New("invalid tick label, must be a string")
+				}
+
+				properties.Ticks = append(properties.Ticks, types.SliderTick{
+					Value: tickValueFloat64,
+					Label: tickLabelStr,
+				})
+			}
+
+			// Handle property show value.
+			showValue, ok := pm["show_value"]
+			if !ok {
+				return nil, errors.New("missing property show value")
+			}
+			showValueBool, ok := showValue.(bool)
+			if !ok {
+				return nil, errors.New("invalid property show value, must be a boolean")
+			}
+			properties.ShowValue = showValueBool
+
+			// Handle property price, optional.
+			if price, ok := pm["price"]; ok {
+				priceDecimal, ok := interfaceToDecimal(price)
+				if !ok {
+					return nil, errors.New("invalid property price, must be a decimal number")
+				}
+				properties.Price = priceDecimal
+			}
+
+			// Handle property min price, optional.
+			if minPrice, ok := pm["min_price"]; ok {
+				minPriceDecimal, ok := interfaceToDecimal(minPrice)
+				if !ok {
+					return nil, errors.New("invalid property min price, must be a decimal number")
+				}
+				properties.MinPrice = minPriceDecimal
+			}
+
+			// Handle property max price, optional.
+			if maxPrice, ok := pm["max_price"]; ok {
+				maxPriceDecimal, ok := interfaceToDecimal(maxPrice)
+				if !ok {
+					return nil, errors.New("invalid property max price, must be a decimal number")
+				}
+				properties.MaxPrice = maxPriceDecimal
+			}
+
+			// Handle property default, optional and null for none.
+			if defaultp, ok := pm["default"]; ok && defaultp != nil {
+				defaultpFloat64, ok := interfaceToFloat64(defaultp)
+				if !ok {
+					return nil, errors.New("invalid property default, must be a number")
+				}
+				properties.Default = &defaultpFloat64
+			}
+
+			// Handle property prefill param, optional.
+			if prefillParam, ok := pm["prefill_param"]; ok {
+				prefillParamStr, ok := prefillParam.(string)
+				if !ok {
+					return nil, errors.New("invalid property prefill param, must be a string")
+				}
+				properties.PrefillParam = prefillParamStr
+			}
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "rating":
+			module := &types.Rating{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.RatingProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property scale.
+			scale, ok := pm["scale"]
+			if !ok {
+				return nil, errors.New("missing property scale")
+			}
+			scaleFloat64, ok := interfaceToFloat64(scale)
+			if !ok {
+				return nil, errors.New("invalid property scale, must be an integer")
+			}
+			properties.Scale = int(scaleFloat64)
+
+			// Handle property icon.
+			icon, ok := pm["icon"]
+			if !ok {
+				return nil, errors.New("missing property icon")
+			}
+			iconStr, ok := icon.(string)
+			if !ok {
+				return nil, errors.New("invalid property icon, must be a string")
+			}
+			properties.Icon = iconStr
+
+			// Handle property price, optional.
+			if price, ok := pm["price"]; ok {
+				priceDecimal, ok := interfaceToDecimal(price)
+				if !ok {
+					return nil, errors.New("invalid property price, must be a decimal number")
+				}
+				properties.Price = priceDecimal
+			}
+
+			// Handle property min price, optional.
+			if minPrice, ok := pm["min_price"]; ok {
+				minPriceDecimal, ok := interfaceToDecimal(minPrice)
+				if !ok {
+					return nil, errors.New("invalid property min price, must be a decimal number")
+				}
+				properties.MinPrice = minPriceDecimal
+			}
+
+			// Handle property max price, optional.
+			if maxPrice, ok := pm["max_price"]; ok {
+				maxPriceDecimal, ok := interfaceToDecimal(maxPrice)
+				if !ok {
+					return nil, errors.New("invalid property max price, must be a decimal number")
+				}
+				properties.MaxPrice = maxPriceDecimal
+			}
+
+			// Handle property default, optional.
+			if defaultp, ok := pm["default"]; ok {
+				defaultpFloat64, ok := interfaceToFloat64(defaultp)
+				if !ok || defaultpFloat64 != float64(int(defaultpFloat64)) {
+					return nil, errors.New("invalid property default, must be an integer")
+				}
+				properties.Default = int(defaultpFloat64)
+			}
+
+			// Handle property prefill param, optional.
+			if prefillParam, ok := pm["prefill_param"]; ok {
+				prefillParamStr, ok := prefillParam.(string)
+				if !ok {
+					return nil, errors.New("invalid property prefill param, must be a string")
+				}
+				properties.PrefillParam = prefillParamStr
+			}
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "matrix":
+			module := &types.Matrix{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.MatrixProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property cell type.
+			cellType, ok := pm["cell_type"]
+			if !ok {
+				return nil, errors.New("missing property cell type")
+			}
+			cellTypeStr, ok := cellType.(string)
+			if !ok {
+				return nil, errors.New("invalid property cell type, must be a string")
+			}
+			properties.CellType = cellTypeStr
+
+			// Handle property rows.
+			rows, ok := pm["rows"]
+			if !ok {
+				return nil, errors.New("missing property rows")
+			}
+			rowsSlice, ok := rows.([]interface{})
+			if !ok {
+				return nil, errors.New("matrix rows is not an array of objects")
+			}
+			properties.Rows = []types.MatrixRow{}
+			for _, v := range rowsSlice {
+				row, ok := v.(map[string]interface{})
+				if !ok {
+					return nil, errors.New("row is invalid")
+				}
+
+				rowID, ok := row["id"]
+				if !ok {
+					return nil, errors.New("could not get 'id' property of matrix row")
+				}
+				rowIDStr, ok := rowID.(string)
+				if !ok {
+					return nil, errors.New("invalid row ID, must be a string")
+				}
+
+				rowLabel, ok := row["label"]
+				if !ok {
+					return nil, errors.New("could not get 'label' property of matrix row")
+				}
+				rowLabelStr, ok := rowLabel.(string)
+				if !ok {
+					return nil, errors.New("invalid row label, must be a string")
+				}
+
+				rowPrice, ok := row["price"]
+				if !ok {
+					return nil, errors.New("could not get 'price' property of matrix row")
+				}
+				rowPriceDecimal, ok := interfaceToDecimal(rowPrice)
+				if !ok {
+					return nil, errors.New("invalid row price, must be a decimal number")
+				}
+
+				// Price ranges are optional, as they were added later.
+				var rowMinPriceDecimal, rowMaxPriceDecimal types.Decimal
+				if rowMinPrice, ok := row["min_price"]; ok {
+					rowMinPriceDecimal, ok = interfaceToDecimal(rowMinPrice)
+					if !ok {
+						return nil, errors.New("invalid row min price, must be a decimal number")
+					}
+				}
+				if rowMaxPrice, ok := row["max_price"]; ok {
+					rowMaxPriceDecimal, ok = interfaceToDecimal(rowMaxPrice)
+					if !ok {
+						return nil, errors.New("invalid row max price, must be a decimal number")
+					}
+				}
+
+				properties.Rows = append(properties.Rows, types.MatrixRow{
+					ID:       rowIDStr,
+					Label:    rowLabelStr,
+					Price:    rowPriceDecimal,
+					MinPrice: rowMinPriceDecimal,
+					MaxPrice: rowMaxPriceDecimal,
+				})
+			}
+
+			// Handle property columns.
+			columns, ok := pm["columns"]
+			if !ok {
+				return nil, errors.New("missing property columns")
+			}
+			columnsSlice, ok := columns.([]interface{})
+			if !ok {
+				return nil, errors.New("matrix columns is not an array of objects")
+			}
+			properties.Columns = []types.MatrixColumn{}
+			for _, v := range columnsSlice {
+				column, ok := v.(map[string]interface{})
+				if !ok {
+					return nil, errors.New("column is invalid")
+				}
+
+				columnID, ok := column["id"]
+				if !ok {
+					return nil, errors.New("could not get 'id' property of matrix column")
+				}
+				columnIDStr, ok := columnID.(string)
+				if !ok {
+					return nil, errors.New("invalid column ID, must be a string")
+				}
+
+				columnLabel, ok := column["label"]
+				if !ok {
+					return nil, errors.New("could not get 'label' property of matrix column")
+				}
+				columnLabelStr, ok := columnLabel.(string)
+				if !ok {
+					return nil, errors.New("invalid column label, must be a string")
+				}
+
+				properties.Columns = append(properties.Columns, types.MatrixColumn{
+					ID:    columnIDStr,
+					Label: columnLabelStr,
+				})
+			}
+
+			// Handle property min.
+			min, ok := pm["min"]
+			if !ok {
+				return nil, errors.New("missing property min")
+			}
+			minFloat64, ok := interfaceToFloat64(min)
+			if !ok {
+				return nil, errors.New("invalid property min, must be a number")
+			}
+			properties.Min = minFloat64
+
+			// Handle property max.
+			max, ok := pm["max"]
+			if !ok {
+				return nil, errors.New("missing property max")
+			}
+			maxFloat64, ok := interfaceToFloat64(max)
+			if !ok {
+				return nil, errors.New("invalid property max, must be a number")
+			}
+			properties.Max = maxFloat64
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "hidden":
+			module := &types.Hidden{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.HiddenProperties{}
+
+			// Handle property source.
+			source, ok := pm["source"]
+			if !ok {
+				return nil, errors.New("missing property source")
+			}
+			sourceStr, ok := source.(string)
+			if !ok {
+				return nil, errors.New("invalid property source, must be a string")
+			}
+			properties.Source = sourceStr
+
+			// Handle property param.
+			param, ok := pm["param"]
+			if !ok {
+				return nil, errors.New("missing property param")
+			}
+			paramStr, ok := param.(string)
+			if !ok {
+				return nil, errors.New("invalid property param, must be a string")
+			}
+			properties.Param = paramStr
+
+			// Handle property value.
+			value, ok := pm["value"]
+			if !ok {
+				return nil, errors.New("missing property value")
+			}
+			valueStr, ok := value.(string)
+			if !ok {
+				return nil, errors.New("invalid property value, must be a string")
+			}
+			properties.Value = valueStr
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "signature":
+			module := &types.Signature{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.SignatureProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property require name.
+			requireName, ok := pm["require_name"]
+			if !ok {
+				return nil, errors.New("missing property require name")
+			}
+			requireNameBool, ok := requireName.(bool)
+			if !ok {
+				return nil, errors.New("invalid property require name, must be a boolean")
+			}
+			properties.RequireName = requireNameBool
+
+			// Handle property storage.
+			storage, ok := pm["storage"]
+			if !ok {
+				return nil, errors.New("missing property storage")
+			}
+			storageStr, ok := storage.(string)
+			if !ok {
+				return nil, errors.New("invalid property storage, must be a string")
+			}
+			properties.Storage = storageStr
+
+			// Handle property max size.
+			maxSize, ok := pm["max_size"]
+			if !ok {
+				return nil, errors.New("missing property max size")
+			}
+			maxSizeFloat64, ok := interfaceToFloat64(maxSize)
+			if !ok {
+				return nil, errors.New("invalid property max size, must be an integer")
+			}
+			properties.MaxSize = int(maxSizeFloat64)
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "consent":
+			module := &types.Consent{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.ConsentProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property text.
+			text, ok := pm["text"]
+			if !ok {
+				return nil, errors.New("missing property text")
+			}
+			textStr, ok := text.(string)
+			if !ok {
+				return nil, errors.New("invalid property text, must be a string")
+			}
+			properties.Text = textStr
+
+			// Handle property terms url.
+			termsURL, ok := pm["terms_url"]
+			if !ok {
+				return nil, errors.New("missing property terms url")
+			}
+			termsURLStr, ok := termsURL.(string)
+			if !ok {
+				return nil, errors.New("invalid property terms url, must be a string")
+			}
+			properties.TermsURL = termsURLStr
+
+			// Handle property version.
+			version, ok := pm["version"]
+			if !ok {
+				return nil, errors.New("missing property version")
+			}
+			versionStr, ok := version.(string)
+			if !ok {
+				return nil, errors.New("invalid property version, must be a string")
+			}
+			properties.Version = versionStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property hash, which is derived from the content
+			// rather than taken from the input.
+			properties.Hash = types.ConsentHash(properties)
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "page-break":
+			module := &types.PageBreak{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.PageBreakProperties{}
+
+			// Handle property title.
+			title, ok := pm["title"]
+			if !ok {
+				return nil, errors.New("missing property title")
+			}
+			titleStr, ok := title.(string)
+			if !ok {
+				return nil, errors.New("invalid property title, must be a string")
+			}
+			properties.Title = titleStr
+
+			// Handle property next label.
+			nextLabel, ok := pm["next_label"]
+			if !ok {
+				return nil, errors.New("missing property next label")
+			}
+			nextLabelStr, ok := nextLabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property next label, must be a string")
+			}
+			properties.NextLabel = nextLabelStr
+
+			// Handle property back label.
+			backLabel, ok := pm["back_label"]
+			if !ok {
+				return nil, errors.New("missing property back label")
+			}
+			backLabelStr, ok := backLabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property back label, must be a string")
+			}
+			properties.BackLabel = backLabelStr
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "product-list":
+			module := &types.ProductList{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.ProductListProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property required.
+			required, ok := pm["required"]
+			if !ok {
+				return nil, errors.New("missing property required")
+			}
+			requiredBool, ok := required.(bool)
+			if !ok {
+				return nil, errors.New("invalid property required, must be a boolean")
+			}
+			properties.Required = requiredBool
+
+			// Handle property items.
+			items, ok := pm["items"]
+			if !ok {
+				return nil, errors.New("missing property items")
+			}
+			itemsSlice, ok := items.([]interface{})
+			if !ok {
+				return nil, errors.New("invalid property items, must be an array of strings")
+			}
+			properties.Items = []string{}
+			for _, v := range itemsSlice {
+				vStr, ok := v.(string)
+				if !ok {
+					return nil, errors.New("invalid property items, must be an array of strings")
+				}
+				properties.Items = append(properties.Items, vStr)
+			}
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "section":
+			module := &types.Section{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.SectionProperties{}
+
+			// Handle property title.
+			title, ok := pm["title"]
+			if !ok {
+				return nil, errors.New("missing property title")
+			}
+			titleStr, ok := title.(string)
+			if !ok {
+				return nil, errors.New("invalid property title, must be a string")
+			}
+			properties.Title = titleStr
+
+			// Handle property description.
+			description, ok := pm["description"]
+			if !ok {
+				return nil, errors.New("missing property description")
+			}
+			descriptionStr, ok := description.(string)
+			if !ok {
+				return nil, errors.New("invalid property description, must be a string")
+			}
+			properties.Description = descriptionStr
+
+			// Handle property collapsible.
+			collapsible, ok := pm["collapsible"]
+			if !ok {
+				return nil, errors.New("missing property collapsible")
+			}
+			collapsibleBool, ok := collapsible.(bool)
+			if !ok {
+				return nil, errors.New("invalid property collapsible, must be a boolean")
+			}
+			properties.Collapsible = collapsibleBool
+
+			// Handle property collapsed.
+			collapsed, ok := pm["collapsed"]
+			if !ok {
+				return nil, errors.New("missing property collapsed")
+			}
+			collapsedBool, ok := collapsed.(bool)
+			if !ok {
+				return nil, errors.New("invalid property collapsed, must be a boolean")
+			}
+			properties.Collapsed = collapsedBool
+
+			// Handle property visible if, optional.
+			if visibleIf, ok := pm["visible_if"]; ok && visibleIf != nil {
+				condition, err := interfaceToCondition(visibleIf)
+				if err != nil {
+					return nil, err
+				}
+				properties.VisibleIf = condition
+			}
+
+			// Handle child modules.
+			children, ok := m["modules"]
+			if !ok {
+				return nil, errors.New("missing modules for section")
+			}
+			childrenSlice, ok := children.([]interface{})
+			if !ok {
+				return nil, errors.New("invalid section modules, must be an array")
+			}
+			childModules, err := s.InterfaceToModules(childrenSlice)
+			if err != nil {
+				return nil, err
+			}
+			module.Modules = childModules
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		case "repeater":
+			module := &types.Repeater{}
+
+			// Handle type.
+			typeStr, ok := t.(string)
+			if !ok {
+				return nil, errors.New("invalid type property, must be a string")
+			}
+			module.Type = typeStr
+
+			// Handle name.
+			name, ok := m["name"]
+			if !ok {
+				return nil, errors.New("missing name for module")
+			}
+			nameStr, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid module name, must be a string")
+			}
+			module.Name = nameStr
+
+			// Handle properties.
+			p, ok := m["properties"]
+			if !ok {
+				return nil, errors.New("missing properties")
+			}
+			pm, ok := p.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid module properties")
+			}
+
+			properties := types.RepeaterProperties{}
+
+			// Handle property label.
+			label, ok := pm["label"]
+			if !ok {
+				return nil, errors.New("missing property label")
+			}
+			labelStr, ok := label.(string)
+			if !ok {
+				return nil, errors.New("invalid property label, must be a string")
+			}
+			properties.Label = labelStr
+
+			// Handle property sublabel.
+			sublabel, ok := pm["sublabel"]
+			if !ok {
+				return nil, errors.New("missing property sublabel")
+			}
+			sublabelStr, ok := sublabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property sublabel, must be a string")
+			}
+			properties.Sublabel = sublabelStr
+
+			// Handle property tooltip.
+			tooltip, ok := pm["tooltip"]
+			if !ok {
+				return nil, errors.New("missing property tooltip")
+			}
+			tooltipStr, ok := tooltip.(string)
+			if !ok {
+				return nil, errors.New("invalid property tooltip, must be a string")
+			}
+			properties.Tooltip = tooltipStr
+
+			// Handle property min items.
+			minItems, ok := pm["min_items"]
+			if !ok {
+				return nil, errors.New("missing property min items")
+			}
+			minItemsFloat64, ok := interfaceToFloat64(minItems)
+			if !ok {
+				return nil, errors.New("invalid property min items, must be an integer")
+			}
+			properties.MinItems = int(minItemsFloat64)
+
+			// Handle property max items.
+			maxItems, ok := pm["max_items"]
+			if !ok {
+				return nil, errors.New("missing property max items")
+			}
+			maxItemsFloat64, ok := interfaceToFloat64(maxItems)
+			if !ok {
+				return nil, errors.New("invalid property max items, must be an integer")
+			}
+			properties.MaxItems = int(maxItemsFloat64)
+
+			// Handle property item label.
+			itemLabel, ok := pm["item_label"]
+			if !ok {
+				return nil, errors.New("missing property item label")
+			}
+			itemLabelStr, ok := itemLabel.(string)
+			if !ok {
+				return nil, errors.New("invalid property item label, must be a string")
+			}
+			properties.ItemLabel = itemLabelStr
+
+			// Handle property item price.
+			itemPrice, ok := pm["item_price"]
+			if !ok {
+				return nil, errors.New("missing property item price")
+			}
+			itemPriceDecimal, ok := interfaceToDecimal(itemPrice)
+			if !ok {
+				return nil, errors.New("invalid property item price, must be a decimal number")
+			}
+			properties.ItemPrice = itemPriceDecimal
+
+			// Handle property item min price, optional.
+			if itemMinPrice, ok := pm["item_min_price"]; ok {
+				itemMinPriceDecimal, ok := interfaceToDecimal(itemMinPrice)
+				if !ok {
+					return nil, errors.New("invalid property item min price, must be a decimal number")
+				}
+				properties.ItemMinPrice = itemMinPriceDecimal
+			}
+
+			// Handle property item max price, optional.
+			if itemMaxPrice, ok := pm["item_max_price"]; ok {
+				itemMaxPriceDecimal, ok := interfaceToDecimal(itemMaxPrice)
+				if !ok {
+					return nil, errors.New("invalid property item max price, must be a decimal number")
+				}
+				properties.ItemMaxPrice = itemMaxPriceDecimal
+			}
+
+			// Handle property quantity module.
+			quantityModule, ok := pm["quantity_module"]
+			if !ok {
+				return nil, errors.New("missing property quantity module")
+			}
+			quantityModuleStr, ok := quantityModule.(string)
+			if !ok {
+				return nil, errors.New("invalid property quantity module, must be a string")
+			}
+			properties.QuantityModule = quantityModuleStr
+
+			// Handle property quantity formula, optional.
+			if quantityFormula, ok := pm["quantity_formula"]; ok {
+				quantityFormulaStr, ok := quantityFormula.(string)
+				if !ok {
+					return nil, errors.New("invalid property quantity formula, must be a string")
+				}
+				properties.QuantityFormula = quantityFormulaStr
+			}
+
+			// Handle child modules.
+			children, ok := m["modules"]
+			if !ok {
+				return nil, errors.New("missing modules for repeater")
+			}
+			childrenSlice, ok := children.([]interface{})
+			if !ok {
+				return nil, errors.New("invalid repeater modules, must be an array")
+			}
+			childModules, err := s.InterfaceToModules(childrenSlice)
+			if err != nil {
+				return nil, err
+			}
+			module.Modules = childModules
+
+			// Set the properties.
+			module.Properties = properties
+
+			modules = append(modules, module)
+		default:
+			return nil, errors.New("invalid module type")
+		}
+
+		// Handle ID, optional.
+		if id, ok := m["id"]; ok && id != nil {
+			idStr, ok := id.(string)
+			if !ok {
+				return nil, errors.New("invalid module ID, must be a string")
+			}
+			modules[len(modules)-1].SetID(idStr)
+		}
+	}
+
+	return modules, nil
+}
+
+// interfaceToCondition takes in an interface{} and converts it to a
+// condition.
+func interfaceToCondition(i interface{}) (*types.Condition, error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid condition, must be an object")
+	}
+
+	condition := &types.Condition{}
+
+	// Handle module.
+	module, ok := m["module"]
+	if !ok {
+		return nil, errors.New("missing condition module")
+	}
+	moduleStr, ok := module.(string)
+	if !ok {
+		return nil, errors.New("invalid condition module, must be a string")
+	}
+	condition.Module = moduleStr
+
+	// Handle operator.
+	operator, ok := m["operator"]
+	if !ok {
+		return nil, errors.New("missing condition operator")
+	}
+	operatorStr, ok := operator.(string)
+	if !ok {
+		return nil, errors.New("invalid condition operator, must be a string")
+	}
+	condition.Operator = operatorStr
+
+	// Handle value, optional for the empty and not_empty operators. Numbers
+	// are compared as floats, like the answers.
+	condition.Value = numbersToFloat64(m["value"])
+
+	return condition, nil
+}
+
+// interfaceToDecimal takes in an interface{} holding a number, or a string
+// holding a number, and converts it to an exact decimal. Numbers decoded as
+// json.Number are parsed as written, so they are exact whatever their size.
+func interfaceToDecimal(i interface{}) (types.Decimal, bool) {
+	switch v := i.(type) {
+	case float64:
+		d, err := types.DecimalFromFloat(v)
+		return d, err == nil
+	case json.Number:
+		d, err := types.ParseDecimal(v.String())
+		if err == nil {
+			return d, true
+		}
+
+		// Handle numbers in exponent form, such as 1e3.
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		d, err = types.DecimalFromFloat(f)
+		return d, err == nil
+	case string:
+		d, err := types.ParseDecimal(v)
+		return d, err == nil
+	}
+
+	return 0, false
+}
+
+// interfaceToFloat64 takes in an interface{} holding a number, decoded as a
+// float64 or a json.Number, and converts it to a float64.
+func interfaceToFloat64(i interface{}) (float64, bool) {
+	switch v := i.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+// numbersToFloat64 converts the json.Number values in the given value,
+// including those in arrays and objects, to float64.
+func numbersToFloat64(i interface{}) interface{} {
+	switch v := i.(type) {
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for k, e := range v {
+			c[k] = numbersToFloat64(e)
+		}
+		return c
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			c[k] = numbersToFloat64(e)
+		}
+		return c
+	}
+
+	return i
 }
@@ -0,0 +1,228 @@
+package form
+
+import (
+	"errors"
+
+	"estimator/storage/form"
+	"estimator/types"
+
+	"github.com/google/uuid"
+)
+
+// GetModule gets the module with the given ID from the working draft of the
+// form with the given ID, searching nested modules as well.
+func (s *Service) GetModule(id, moduleID string) (types.Module, error) {
+	// Get the draft.
+	f, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the module.
+	modules, i := findModule(&f.Modules, moduleID)
+	if modules == nil {
+		return nil, types.ErrModuleNotFound
+	}
+
+	return (*modules)[i], nil
+}
+
+// InsertModule inserts the module at the given position among the children
+// of the parent module with the given ID, or among the top level modules of
+// the form when the parent ID is empty. A negative position, or one past the
+// end, appends the module. The module and its children are given new IDs.
+// The version must be the current version of the form.
+func (s *Service) InsertModule(id, memberID string, version int, parentID string, position int, module types.Module) (*types.Form, types.Module, error) {
+	f, err := s.updateModules(id, memberID, version, func(f *types.Form) error {
+		// Get the children of the parent.
+		modules, err := childrenOf(&f.Modules, parentID)
+		if err != nil {
+			return err
+		}
+
+		// Set IDs.
+		for _, m := range types.AllModules([]types.Module{module}) {
+			m.SetID(uuid.NewString())
+		}
+
+		// Insert the module.
+		if position < 0 || position > len(*modules) {
+			position = len(*modules)
+		}
+		*modules = append(*modules, nil)
+		copy((*modules)[position+1:], (*modules)[position:])
+		(*modules)[position] = module
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, module, nil
+}
+
+// UpdateModule replaces the module with the given ID in the working draft
+// of the form with the given ID. The module keeps its ID, and any of its
+// children without an ID are given one. The version must be the current
+// version of the form.
+func (s *Service) UpdateModule(id, memberID string, version int, moduleID string, module types.Module) (*types.Form, types.Module, error) {
+	f, err := s.updateModules(id, memberID, version, func(f *types.Form) error {
+		// Find the module.
+		modules, i := findModule(&f.Modules, moduleID)
+		if modules == nil {
+			return types.ErrModuleNotFound
+		}
+
+		// Keep the module ID, the update gives its new children one.
+		module.SetID(moduleID)
+
+		// Replace the module.
+		(*modules)[i] = module
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, module, nil
+}
+
+// DeleteModule removes the module with the given ID, along with its
+// children, from the working draft of the form with the given ID. The
+// version must be the current version of the form.
+func (s *Service) DeleteModule(id, memberID string, version int, moduleID string) (*types.Form, error) {
+	return s.updateModules(id, memberID, version, func(f *types.Form) error {
+		// Find the module.
+		modules, i := findModule(&f.Modules, moduleID)
+		if modules == nil {
+			return types.ErrModuleNotFound
+		}
+
+		// Remove the module.
+		*modules = append((*modules)[:i], (*modules)[i+1:]...)
+
+		return nil
+	})
+}
+
+// ReorderModules reorders the children of the parent module with the given
+// ID, or the top level modules of the form when the parent ID is empty, to
+// the order of the given module IDs. The IDs must list every child exactly
+// once. The version must be the current version of the form.
+func (s *Service) ReorderModules(id, memberID string, version int, parentID string, order []string) (*types.Form, error) {
+	return s.updateModules(id, memberID, version, func(f *types.Form) error {
+		// Get the children of the parent.
+		modules, err := childrenOf(&f.Modules, parentID)
+		if err != nil {
+			return err
+		}
+
+		// Check the order lists every child exactly once.
+		if len(order) != len(*modules) {
+			return errors.New("order must list every module exactly once")
+		}
+		byID := make(map[string]types.Module)
+		for _, module := range *modules {
+			byID[module.GetID()] = module
+		}
+
+		// Reorder the modules.
+		reordered := []types.Module{}
+		for _, moduleID := range order {
+			module, ok := byID[moduleID]
+			if !ok {
+				return errors.New("order must list every module exactly once")
+			}
+			delete(byID, moduleID)
+			reordered = append(reordered, module)
+		}
+		*modules = reordered
+
+		return nil
+	})
+}
+
+// updateModules applies the given change to the modules of the working
+// draft of the form with the given ID, and saves the result as an update
+// by the member.
+func (s *Service) updateModules(id, memberID string, version int, change func(f *types.Form) error) (*types.Form, error) {
+	// Get the draft.
+	f, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if f.Version != version {
+		return nil, form.ErrVersionConflict
+	}
+
+	// Apply the change.
+	if err := change(f); err != nil {
+		return nil, err
+	}
+
+	// Update the form.
+	return s.UpdateByIDAndMemberID(id, memberID, &types.Form{
+		Modules: f.Modules,
+		Pricing: f.Pricing,
+		Version: version,
+	})
+}
+
+// childrenOf returns the children of the parent module with the given ID,
+// or the given modules when the parent ID is empty.
+func childrenOf(modules *[]types.Module, parentID string) (*[]types.Module, error) {
+	if parentID == "" {
+		return modules, nil
+	}
+
+	// Find the parent.
+	parents, i := findModule(modules, parentID)
+	if parents == nil {
+		return nil, types.ErrModuleNotFound
+	}
+
+	// Get its children.
+	children := childModules((*parents)[i])
+	if children == nil {
+		return nil, errors.New("parent module can not hold child modules")
+	}
+
+	return children, nil
+}
+
+// findModule returns the modules holding the module with the given ID,
+// searching nested modules as well, and its index. The modules are nil when
+// the module could not be found.
+func findModule(modules *[]types.Module, moduleID string) (*[]types.Module, int) {
+	if moduleID == "" {
+		return nil, -1
+	}
+
+	for i, module := range *modules {
+		if module.GetID() == moduleID {
+			return modules, i
+		}
+		if children := childModules(module); children != nil {
+			if found, j := findModule(children, moduleID); found != nil {
+				return found, j
+			}
+		}
+	}
+
+	return nil, -1
+}
+
+// childModules returns the child modules of the given module, or nil when
+// the module can not hold child modules.
+func childModules(module types.Module) *[]types.Module {
+	switch m := module.(type) {
+	case *types.Section:
+		return &m.Modules
+	case *types.Repeater:
+		return &m.Modules
+	}
+
+	return nil
+}
@@ -0,0 +1,426 @@
+package form
+
+import (
+	"errors"
+	"time"
+
+	"estimator/types"
+)
+
+// InterfaceToPricing takes in an interface{} and converts it to the form
+// pricing settings. A nil interface, such as from forms created before
+// pricing settings existed, returns empty settings.
+func (s *Service) InterfaceToPricing(i interface{}) (*types.Pricing, error) {
+	p := &types.Pricing{
+		DisplayCurrencies: []string{},
+		Formulas:          []types.FormulaPrice{},
+		Taxes:             []types.Tax{},
+		Discounts:         []types.Adjustment{},
+		Coupons:           []types.Coupon{},
+		Fees:              []types.Adjustment{},
+	}
+
+	// Handle no pricing settings.
+	if i == nil {
+		return p, nil
+	}
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid pricing, must be an object")
+	}
+
+	// Handle currency, optional.
+	if currency, ok := m["currency"]; ok && currency != nil {
+		currencyStr, ok := currency.(string)
+		if !ok {
+			return nil, errors.New("invalid pricing currency, must be a string")
+		}
+		p.Currency = currencyStr
+	}
+
+	// Handle display currencies, optional.
+	if displayCurrencies, ok := m["display_currencies"]; ok && displayCurrencies != nil {
+		displayCurrenciesSlice, ok := displayCurrencies.([]interface{})
+		if !ok {
+			return nil, errors.New("invalid pricing display currencies, must be an array of strings")
+		}
+		for _, v := range displayCurrenciesSlice {
+			displayCurrencyStr, ok := v.(string)
+			if !ok {
+				return nil, errors.New("invalid display currency, must be a string")
+			}
+			p.DisplayCurrencies = append(p.DisplayCurrencies, displayCurrencyStr)
+		}
+	}
+
+	// Handle rounding, optional.
+	if rounding, ok := m["rounding"]; ok && rounding != nil {
+		roundingStr, ok := rounding.(string)
+		if !ok {
+			return nil, errors.New("invalid pricing rounding, must be a string")
+		}
+		p.Rounding = roundingStr
+	}
+
+	// Handle locale, optional.
+	if locale, ok := m["locale"]; ok && locale != nil {
+		localeStr, ok := locale.(string)
+		if !ok {
+			return nil, errors.New("invalid pricing locale, must be a string")
+		}
+		p.Locale = localeStr
+	}
+
+	// Handle formulas, optional.
+	if formulas, ok := m["formulas"]; ok && formulas != nil {
+		formulasSlice, ok := formulas.([]interface{})
+		if !ok {
+			return nil, errors.New("invalid pricing formulas, must be an array")
+		}
+		for _, v := range formulasSlice {
+			fp, err := interfaceToFormulaPrice(v)
+			if err != nil {
+				return nil, err
+			}
+			p.Formulas = append(p.Formulas, *fp)
+		}
+	}
+
+	// Handle taxes, optional.
+	if taxes, ok := m["taxes"]; ok && taxes != nil {
+		taxesSlice, ok := taxes.([]interface{})
+		if !ok {
+			return nil, errors.New("invalid pricing taxes, must be an array")
+		}
+		for _, v := range taxesSlice {
+			tax, err := interfaceToTax(v)
+			if err != nil {
+				return nil, err
+			}
+			p.Taxes = append(p.Taxes, *tax)
+		}
+	}
+
+	// Handle discounts, optional.
+	if discounts, ok := m["discounts"]; ok && discounts != nil {
+		discountsSlice, ok := discounts.([]interface{})
+		if !ok {
+			return nil, errors.New("invalid pricing discounts, must be an array")
+		}
+		for _, v := range discountsSlice {
+			discount, err := interfaceToAdjustment(v)
+			if err != nil {
+				return nil, err
+			}
+			p.Discounts = append(p.Discounts, *discount)
+		}
+	}
+
+	// Handle coupons, optional.
+	if coupons, ok := m["coupons"]; ok && coupons != nil {
+		couponsSlice, ok := coupons.([]interface{})
+		if !ok {
+			return nil, errors.New("invalid pricing coupons, must be an array")
+		}
+		for _, v := range couponsSlice {
+			coupon, err := interfaceToCoupon(v)
+			if err != nil {
+				return nil, err
+			}
+			p.Coupons = append(p.Coupons, *coupon)
+		}
+	}
+
+	// Handle fees, optional.
+	if fees, ok := m["fees"]; ok && fees != nil {
+		feesSlice, ok := fees.([]interface{})
+		if !ok {
+			return nil, errors.New("invalid pricing fees, must be an array")
+		}
+		for _, v := range feesSlice {
+			fee, err := interfaceToAdjustment(v)
+			if err != nil {
+				return nil, err
+			}
+			p.Fees = append(p.Fees, *fee)
+		}
+	}
+
+	// Handle minimum charge, optional.
+	if minimumCharge, ok := m["minimum_charge"]; ok && minimumCharge != nil {
+		minimumChargeDecimal, ok := interfaceToDecimal(minimumCharge)
+		if !ok {
+			return nil, errors.New("invalid pricing minimum charge, must be a decimal number")
+		}
+		p.MinimumCharge = minimumChargeDecimal
+	}
+
+	return p, nil
+}
+
+// interfaceToFormulaPrice takes in an interface{} and converts it to a
+// formula price.
+func interfaceToFormulaPrice(i interface{}) (*types.FormulaPrice, error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid formula, must be an object")
+	}
+
+	fp := &types.FormulaPrice{}
+
+	// Handle formula name.
+	name, ok := m["name"]
+	if !ok {
+		return nil, errors.New("missing formula name")
+	}
+	nameStr, ok := name.(string)
+	if !ok {
+		return nil, errors.New("invalid formula name, must be a string")
+	}
+	fp.Name = nameStr
+
+	// Handle formula formula.
+	formula, ok := m["formula"]
+	if !ok {
+		return nil, errors.New("missing formula formula")
+	}
+	formulaStr, ok := formula.(string)
+	if !ok {
+		return nil, errors.New("invalid formula formula, must be a string")
+	}
+	fp.Formula = formulaStr
+
+	// Handle formula price.
+	price, ok := m["price"]
+	if !ok {
+		return nil, errors.New("missing formula price")
+	}
+	priceDecimal, ok := interfaceToDecimal(price)
+	if !ok {
+		return nil, errors.New("invalid formula price, must be a decimal number")
+	}
+	fp.Price = priceDecimal
+
+	// Handle formula min price, optional.
+	if minPrice, ok := m["min_price"]; ok && minPrice != nil {
+		minPriceDecimal, ok := interfaceToDecimal(minPrice)
+		if !ok {
+			return nil, errors.New("invalid formula min price, must be a decimal number")
+		}
+		fp.MinPrice = minPriceDecimal
+	}
+
+	// Handle formula max price, optional.
+	if maxPrice, ok := m["max_price"]; ok && maxPrice != nil {
+		maxPriceDecimal, ok := interfaceToDecimal(maxPrice)
+		if !ok {
+			return nil, errors.New("invalid formula max price, must be a decimal number")
+		}
+		fp.MaxPrice = maxPriceDecimal
+	}
+
+	return fp, nil
+}
+
+// interfaceToTax takes in an interface{} and converts it to a tax.
+func interfaceToTax(i interface{}) (*types.Tax, error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid tax, must be an object")
+	}
+
+	tax := &types.Tax{}
+
+	// Handle tax name.
+	name, ok := m["name"]
+	if !ok {
+		return nil, errors.New("missing tax name")
+	}
+	nameStr, ok := name.(string)
+	if !ok {
+		return nil, errors.New("invalid tax name, must be a string")
+	}
+	tax.Name = nameStr
+
+	// Handle tax rate.
+	rate, ok := m["rate"]
+	if !ok {
+		return nil, errors.New("missing tax rate")
+	}
+	rateDecimal, ok := interfaceToDecimal(rate)
+	if !ok {
+		return nil, errors.New("invalid tax rate, must be a decimal number")
+	}
+	tax.Rate = rateDecimal
+
+	// Handle tax inclusive.
+	inclusive, ok := m["inclusive"]
+	if !ok {
+		return nil, errors.New("missing tax inclusive")
+	}
+	inclusiveBool, ok := inclusive.(bool)
+	if !ok {
+		return nil, errors.New("invalid tax inclusive, must be a boolean")
+	}
+	tax.Inclusive = inclusiveBool
+
+	// Handle tax per line.
+	perLine, ok := m["per_line"]
+	if !ok {
+		return nil, errors.New("missing tax per line")
+	}
+	perLineBool, ok := perLine.(bool)
+	if !ok {
+		return nil, errors.New("invalid tax per line, must be a boolean")
+	}
+	tax.PerLine = perLineBool
+
+	return tax, nil
+}
+
+// interfaceToAdjustment takes in an interface{} and converts it to an adjustment.
+func interfaceToAdjustment(i interface{}) (*types.Adjustment, error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid adjustment, must be an object")
+	}
+
+	adjustment := &types.Adjustment{}
+
+	// Handle adjustment name.
+	name, ok := m["name"]
+	if !ok {
+		return nil, errors.New("missing adjustment name")
+	}
+	nameStr, ok := name.(string)
+	if !ok {
+		return nil, errors.New("invalid adjustment name, must be a string")
+	}
+	adjustment.Name = nameStr
+
+	// Handle adjustment type.
+	typep, ok := m["type"]
+	if !ok {
+		return nil, errors.New("missing adjustment type")
+	}
+	typepStr, ok := typep.(string)
+	if !ok {
+		return nil, errors.New("invalid adjustment type, must be a string")
+	}
+	adjustment.Type = typepStr
+
+	// Handle adjustment value.
+	value, ok := m["value"]
+	if !ok {
+		return nil, errors.New("missing adjustment value")
+	}
+	valueDecimal, ok := interfaceToDecimal(value)
+	if !ok {
+		return nil, errors.New("invalid adjustment value, must be a decimal number")
+	}
+	adjustment.Value = valueDecimal
+
+	// Handle adjustment condition, optional.
+	if condition, ok := m["condition"]; ok && condition != nil {
+		c, err := interfaceToCondition(condition)
+		if err != nil {
+			return nil, err
+		}
+		adjustment.Condition = c
+	}
+
+	return adjustment, nil
+}
+
+// interfaceToCoupon takes in an interface{} and converts it to a coupon.
+func interfaceToCoupon(i interface{}) (*types.Coupon, error) {
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid coupon, must be an object")
+	}
+
+	coupon := &types.Coupon{}
+
+	// Handle coupon code.
+	code, ok := m["code"]
+	if !ok {
+		return nil, errors.New("missing coupon code")
+	}
+	codeStr, ok := code.(string)
+	if !ok {
+		return nil, errors.New("invalid coupon code, must be a string")
+	}
+	coupon.Code = codeStr
+
+	// Handle coupon name.
+	name, ok := m["name"]
+	if !ok {
+		return nil, errors.New("missing coupon name")
+	}
+	nameStr, ok := name.(string)
+	if !ok {
+		return nil, errors.New("invalid coupon name, must be a string")
+	}
+	coupon.Name = nameStr
+
+	// Handle coupon type.
+	typep, ok := m["type"]
+	if !ok {
+		return nil, errors.New("missing coupon type")
+	}
+	typepStr, ok := typep.(string)
+	if !ok {
+		return nil, errors.New("invalid coupon type, must be a string")
+	}
+	coupon.Type = typepStr
+
+	// Handle coupon value.
+	value, ok := m["value"]
+	if !ok {
+		return nil, errors.New("missing coupon value")
+	}
+	valueDecimal, ok := interfaceToDecimal(value)
+	if !ok {
+		return nil, errors.New("invalid coupon value, must be a decimal number")
+	}
+	coupon.Value = valueDecimal
+
+	// Handle coupon starts at, optional.
+	if startsAt, ok := m["starts_at"]; ok && startsAt != nil && startsAt != "" {
+		startsAtStr, ok := startsAt.(string)
+		if !ok {
+			return nil, errors.New("invalid coupon starts at, must be a string")
+		}
+		t, err := time.Parse(time.RFC3339, startsAtStr)
+		if err != nil {
+			return nil, errors.New("invalid coupon starts at, must be an RFC 3339 time")
+		}
+		coupon.StartsAt = t
+	}
+
+	// Handle coupon ends at, optional.
+	if endsAt, ok := m["ends_at"]; ok && endsAt != nil && endsAt != "" {
+		endsAtStr, ok := endsAt.(string)
+		if !ok {
+			return nil, errors.New("invalid coupon ends at, must be a string")
+		}
+		t, err := time.Parse(time.RFC3339, endsAtStr)
+		if err != nil {
+			return nil, errors.New("invalid coupon ends at, must be an RFC 3339 time")
+		}
+		coupon.EndsAt = t
+	}
+
+	// Handle coupon max uses.
+	maxUses, ok := m["max_uses"]
+	if !ok {
+		return nil, errors.New("missing coupon max uses")
+	}
+	maxUsesFloat64, ok := maxUses.(float64)
+	if !ok {
+		return nil, errors.New("invalid coupon max uses, must be an integer")
+	}
+	coupon.MaxUses = int(maxUsesFloat64)
+
+	return coupon, nil
+}
@@ -0,0 +1,115 @@
+package form
+
+import (
+	"errors"
+
+	"estimator/storage/formrevision"
+	"estimator/types"
+)
+
+// GetRevisions gets a page of the revisions of the form with the given ID,
+// newest first.
+func (s *Service) GetRevisions(id string, limit, offset int) ([]*types.FormRevision, error) {
+	// Check the form exists.
+	if _, err := s.s.Form.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	// Get the revisions from storage.
+	srs, err := s.s.FormRevision.Get(id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map to revisions.
+	revisions := []*types.FormRevision{}
+	for _, sr := range srs {
+		r, err := s.storageToRevision(sr)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision gets the revision with the given number of the form with the
+// given ID.
+func (s *Service) GetRevision(id string, number int) (*types.FormRevision, error) {
+	// Get the revision from storage.
+	sr, err := s.s.FormRevision.GetByNumber(id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storageToRevision(sr)
+}
+
+// Diff returns the differences between the from and to revisions of the
+// form with the given ID.
+func (s *Service) Diff(id string, from, to int) (*types.FormDiff, error) {
+	// Get the revisions.
+	a, err := s.GetRevision(id, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.GetRevision(id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.DiffForms(a, b)
+}
+
+// Revert reverts the form with the given ID to the revision with the given
+// number. The revision is saved as a new revision authored by the member,
+// so the history is kept and the revert can be undone in turn. The version
+// must be the current version of the form.
+func (s *Service) Revert(id string, number, version int, memberID string) (*types.Form, error) {
+	// Get the revision.
+	r, err := s.GetRevision(id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the form, keeping the IDs of the revision.
+	return s.update(id, memberID, &types.Form{
+		Modules: r.Form.Modules,
+		Pricing: r.Form.Pricing,
+		Version: version,
+	}, r.Form.Modules)
+}
+
+// storageToRevision maps a storage form revision to a form revision.
+func (s *Service) storageToRevision(sr *formrevision.Revision) (*types.FormRevision, error) {
+	// Convert interface to the form definition.
+	modules, ok := sr.Modules.([]interface{})
+	if !ok {
+		return nil, errors.New("invalid form revision modules")
+	}
+	m, err := s.InterfaceToModules(modules)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.InterfaceToPricing(sr.Pricing)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new form.
+	f := &types.Form{
+		ID:       sr.FormID,
+		Modules:  m,
+		Pricing:  p,
+		Revision: sr.Number,
+	}
+
+	return &types.FormRevision{
+		FormID:  sr.FormID,
+		Number:  sr.Number,
+		Author:  sr.Author,
+		Form:    f,
+		Created: sr.Created,
+	}, nil
+}
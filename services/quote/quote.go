@@ -0,0 +1,538 @@
+package quote
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"estimator/services/estimate"
+	"estimator/services/form"
+	"estimator/services/submission"
+	"estimator/storage"
+	"estimator/storage/quote"
+	"estimator/types"
+
+	"github.com/google/uuid"
+)
+
+// tokenSize defines the number of random bytes in a quote public token.
+const tokenSize = 32
+
+// Service defines the quote service.
+type Service struct {
+	s          *storage.Storage
+	form       *form.Service
+	estimate   *estimate.Service
+	submission *submission.Service
+
+	mu          sync.RWMutex
+	subscribers []func(*types.QuoteEvent)
+}
+
+// New creates a new service.
+func New(s *storage.Storage, f *form.Service, e *estimate.Service, sub *submission.Service) *Service {
+	return &Service{
+		s:          s,
+		form:       f,
+		estimate:   e,
+		submission: sub,
+	}
+}
+
+// Subscribe registers a function that is called with every quote event.
+// Functions are called synchronously, in the order they were registered, so
+// they should hand off slow work.
+func (s *Service) Subscribe(fn func(*types.QuoteEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// publish calls the subscribers with an event of the given type.
+func (s *Service) publish(typ string, q *types.Quote) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e := &types.QuoteEvent{
+		Type:  typ,
+		Quote: q,
+		At:    q.Updated,
+	}
+	for _, fn := range s.subscribers {
+		fn(e)
+	}
+}
+
+// Create creates a new draft quote for the form with the given ID,
+// snapshotting the form and the estimate for the given answers. The answers
+// are validated as for a submission, with hidden module answers set from
+// the form. The coupon and currency are applied as for an estimate. The
+// quote expires at the given time, or after DefaultQuoteValidity when zero.
+// Customers must sign to accept the quote when requireSignature is set.
+func (s *Service) Create(formID string, answers map[string]interface{}, code, currency string, expiresAt time.Time, requireSignature bool) (*types.Quote, error) {
+	now := time.Now().UTC()
+
+	// Check the expiry date.
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(types.DefaultQuoteValidity)
+	}
+	if !expiresAt.After(now) {
+		return nil, errors.New("quote expiry date must be in the future")
+	}
+
+	// Get the published form.
+	f, err := s.form.GetPublished(formID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set hidden module answers, which are never taken from the client.
+	if answers == nil {
+		answers = map[string]interface{}{}
+	}
+	for _, module := range types.Flatten(f.Modules) {
+		if h, ok := module.(*types.Hidden); ok {
+			answers[h.Name] = h.Properties.Value
+		}
+	}
+
+	// Validate the answers.
+	if err := s.submission.ValidateAnswers(f, answers); err != nil {
+		return nil, err
+	}
+
+	// Compute the estimate.
+	e, err := s.estimate.EstimateForm(f, answers, code, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the quote number.
+	n, err := s.s.Quote.NextNumber(now.Year())
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the public token.
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	q := &types.Quote{
+		ID:               uuid.NewString(),
+		Number:           types.QuoteNumber(now.Year(), n),
+		Token:            token,
+		Form:             f,
+		Answers:          answers,
+		Estimate:         e,
+		Status:           types.QuoteDraft,
+		ExpiresAt:        expiresAt.UTC(),
+		RequireSignature: requireSignature,
+		Created:          now,
+		Updated:          now,
+	}
+
+	// Create in storage.
+	if _, err := s.s.Quote.Create(quoteToStorage(q)); err != nil {
+		return nil, err
+	}
+	s.publish(types.QuoteEventCreated, q)
+
+	return q, nil
+}
+
+// Get gets a page of quotes, newest first, optionally filtered by form ID
+// and status.
+func (s *Service) Get(formID, status string, limit, offset int) ([]*types.Quote, error) {
+	// Check the status.
+	if status != "" {
+		if err := types.ValidateQuoteStatus(status); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get the quotes from storage.
+	sqs, err := s.s.Quote.Get(formID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map to quotes.
+	quotes := []*types.Quote{}
+	for _, sq := range sqs {
+		q, err := s.storageToQuote(sq)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, q)
+	}
+
+	return quotes, nil
+}
+
+// GetByID gets a quote by the given ID.
+func (s *Service) GetByID(id string) (*types.Quote, error) {
+	// Get the quote from storage.
+	sq, err := s.s.Quote.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storageToQuote(sq)
+}
+
+// GetByToken gets a quote by the given public token. Draft quotes have not
+// been sent to the customer yet, so they are not found.
+func (s *Service) GetByToken(token string) (*types.Quote, error) {
+	// Get the quote from storage.
+	sq, err := s.s.Quote.GetByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if sq.Status == types.QuoteDraft {
+		return nil, quote.ErrQuoteNotFound
+	}
+
+	return s.storageToQuote(sq)
+}
+
+// Transition moves the quote with the given ID to the given status. Draft
+// quotes can be sent, and open quotes expired. Quotes can only be accepted
+// or declined by the customer, see Respond.
+func (s *Service) Transition(id, status string) (*types.Quote, error) {
+	// Check the status.
+	if err := types.ValidateQuoteStatus(status); err != nil {
+		return nil, err
+	}
+	if status == types.QuoteAccepted || status == types.QuoteDeclined {
+		return nil, types.ErrInvalidTransition
+	}
+
+	// Get the quote.
+	q, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !q.CanTransition(status) {
+		return nil, types.ErrInvalidTransition
+	}
+
+	// Update in storage.
+	now := time.Now().UTC()
+	if err := s.s.Quote.UpdateStatus(id, q.Status, status, now); err != nil {
+		return nil, err
+	}
+	q.Status, q.Updated = status, now
+	s.publish("quote."+status, q)
+
+	return q, nil
+}
+
+// Expire expires the open quotes past their expiry date, publishing a
+// quote.expired event for each. Quotes changed by another request in the
+// meantime are left as they are.
+func (s *Service) Expire() error {
+	// Get the open quotes past their expiry date.
+	now := time.Now().UTC()
+	sqs, err := s.s.Quote.GetExpired(now)
+	if err != nil {
+		return err
+	}
+
+	// Expire each quote.
+	for _, sq := range sqs {
+		if err := s.s.Quote.UpdateStatus(sq.ID, sq.Status, types.QuoteExpired, now); err != nil {
+			if err == quote.ErrStatusChanged {
+				continue
+			}
+			return err
+		}
+		q, err := s.storageToQuote(sq)
+		if err != nil {
+			return err
+		}
+		q.Status, q.Updated = types.QuoteExpired, now
+		s.publish("quote."+types.QuoteExpired, q)
+	}
+
+	return nil
+}
+
+// Respond records the customer response to the sent quote with the given
+// public token, accepting or declining it. The signer name is required to
+// accept, and the signature when the quote requires one. The quote can't
+// change once it has been responded to.
+func (s *Service) Respond(token, status, name string, signature map[string]interface{}, ip, userAgent string) (*types.Quote, error) {
+	// Check the status.
+	if status != types.QuoteAccepted && status != types.QuoteDeclined {
+		return nil, errors.New("invalid quote response status")
+	}
+
+	// Get the quote.
+	q, err := s.GetByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if q.Status != types.QuoteSent {
+		return nil, types.ErrInvalidTransition
+	}
+
+	now := time.Now().UTC()
+	res := &types.QuoteResponse{
+		Status:    status,
+		Name:      strings.TrimSpace(name),
+		IP:        ip,
+		UserAgent: userAgent,
+		At:        now,
+	}
+
+	// Handle acceptance.
+	if status == types.QuoteAccepted {
+		if res.Name == "" {
+			return nil, errors.New("name is required to accept a quote")
+		}
+
+		// Check the signature, signed by the named customer.
+		var answer interface{}
+		if signature != nil {
+			signature["name"] = res.Name
+			answer = signature
+		}
+		if err := q.SignatureModule().ValidateAnswer(answer); err != nil {
+			return nil, err
+		}
+
+		// Record the signing time, the IP is only kept on the response.
+		if signature != nil {
+			signature["signed_at"] = now.Format(time.RFC3339)
+			res.Signature = signature
+		}
+	}
+
+	// Update in storage.
+	if err := s.s.Quote.Respond(q.ID, q.Status, responseToStorage(res), now); err != nil {
+		if err == quote.ErrStatusChanged {
+			return nil, types.ErrInvalidTransition
+		}
+		return nil, err
+	}
+	q.Status, q.Response, q.Updated = status, res, now
+	s.publish("quote."+status, q)
+
+	return q, nil
+}
+
+// newToken generates an unguessable, URL safe quote public token.
+func newToken() (string, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// responseToStorage maps a quote response to a storage quote response.
+func responseToStorage(r *types.QuoteResponse) *quote.Response {
+	if r == nil {
+		return nil
+	}
+
+	res := &quote.Response{
+		Status:    r.Status,
+		Name:      r.Name,
+		IP:        r.IP,
+		UserAgent: r.UserAgent,
+		At:        r.At,
+	}
+	if r.Signature != nil {
+		res.Signature = r.Signature
+	}
+
+	return res
+}
+
+// storageToResponse maps a storage quote response to a quote response.
+func storageToResponse(sr *quote.Response) *types.QuoteResponse {
+	if sr == nil {
+		return nil
+	}
+
+	signature, _ := sr.Signature.(map[string]interface{})
+	return &types.QuoteResponse{
+		Status:    sr.Status,
+		Name:      sr.Name,
+		Signature: signature,
+		IP:        sr.IP,
+		UserAgent: sr.UserAgent,
+		At:        sr.At,
+	}
+}
+
+// quoteToStorage maps a quote to a storage quote.
+func quoteToStorage(q *types.Quote) *quote.Quote {
+	e := q.Estimate
+	se := &quote.Estimate{
+		Lines:    []quote.Line{},
+		Coupon:   e.Coupon,
+		Currency: e.Currency,
+		Locale:   e.Locale,
+		Subtotal: e.Subtotal.Amount,
+		Tax:      e.Tax.Amount,
+		Total:    e.Total.Amount,
+		Low:      e.Low.Amount,
+		High:     e.High.Amount,
+	}
+	for _, v := range e.Lines {
+		se.Lines = append(se.Lines, quote.Line{
+			Kind:        v.Kind,
+			Module:      v.Module,
+			Description: v.Description,
+			SKU:         v.SKU,
+			Quantity:    v.Quantity,
+			Unit:        v.Unit,
+			UnitPrice:   v.UnitPrice.String(),
+			Amount:      v.Amount.Amount,
+			Low:         v.Low.Amount,
+			High:        v.High.Amount,
+			Included:    v.Included,
+		})
+	}
+	if e.Conversion != nil {
+		se.Conversion = &quote.Conversion{
+			From:    e.Conversion.From,
+			To:      e.Conversion.To,
+			Rate:    e.Conversion.Rate.String(),
+			Updated: e.Conversion.Updated,
+		}
+	}
+
+	return &quote.Quote{
+		ID:               q.ID,
+		Number:           q.Number,
+		Token:            q.Token,
+		FormID:           q.Form.ID,
+		FormRevision:     q.Form.Revision,
+		FormVersion:      q.Form.Version,
+		FormModules:      q.Form.Modules,
+		FormPricing:      q.Form.Pricing,
+		Answers:          q.Answers,
+		Estimate:         se,
+		Status:           q.Status,
+		ExpiresAt:        q.ExpiresAt,
+		RequireSignature: q.RequireSignature,
+		Response:         responseToStorage(q.Response),
+		Created:          q.Created,
+		Updated:          q.Updated,
+	}
+}
+
+// storageToQuote maps a storage quote to a quote.
+func (s *Service) storageToQuote(sq *quote.Quote) (*types.Quote, error) {
+	// Convert interface to the form snapshot.
+	modules, ok := sq.FormModules.([]interface{})
+	if !ok {
+		return nil, errors.New("invalid quote form modules")
+	}
+	m, err := s.form.InterfaceToModules(modules)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.form.InterfaceToPricing(sq.FormPricing)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the answers.
+	answers, _ := sq.Answers.(map[string]interface{})
+	if answers == nil {
+		answers = map[string]interface{}{}
+	}
+
+	// Map the estimate.
+	e, err := storageToEstimate(sq.FormID, sq.Estimate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Quote{
+		ID:     sq.ID,
+		Number: sq.Number,
+		Token:  sq.Token,
+		Form: &types.Form{
+			ID:       sq.FormID,
+			Modules:  m,
+			Pricing:  p,
+			Revision: sq.FormRevision,
+			Version:  sq.FormVersion,
+		},
+		Answers:          answers,
+		Estimate:         e,
+		Status:           sq.Status,
+		ExpiresAt:        sq.ExpiresAt,
+		RequireSignature: sq.RequireSignature,
+		Response:         storageToResponse(sq.Response),
+		Created:          sq.Created,
+		Updated:          sq.Updated,
+	}, nil
+}
+
+// storageToEstimate maps a storage quote estimate to an estimate.
+func storageToEstimate(formID string, se *quote.Estimate) (*types.Estimate, error) {
+	if se == nil {
+		return nil, errors.New("invalid quote estimate")
+	}
+	money := func(amount int64) types.Money {
+		return types.Money{Amount: amount, Currency: se.Currency}
+	}
+
+	e := &types.Estimate{
+		FormID:   formID,
+		Lines:    []types.EstimateLine{},
+		Coupon:   se.Coupon,
+		Currency: se.Currency,
+		Locale:   se.Locale,
+		Subtotal: money(se.Subtotal),
+		Tax:      money(se.Tax),
+		Total:    money(se.Total),
+		Low:      money(se.Low),
+		High:     money(se.High),
+	}
+	for _, v := range se.Lines {
+		unitPrice, err := types.ParseDecimal(v.UnitPrice)
+		if err != nil {
+			return nil, err
+		}
+		e.Lines = append(e.Lines, types.EstimateLine{
+			Kind:        v.Kind,
+			Module:      v.Module,
+			Description: v.Description,
+			SKU:         v.SKU,
+			Quantity:    v.Quantity,
+			Unit:        v.Unit,
+			UnitPrice:   unitPrice,
+			Amount:      money(v.Amount),
+			Low:         money(v.Low),
+			High:        money(v.High),
+			Included:    v.Included,
+		})
+	}
+	if se.Conversion != nil {
+		rate, err := types.ParseDecimal(se.Conversion.Rate)
+		if err != nil {
+			return nil, err
+		}
+		e.Conversion = &types.Conversion{
+			From:    se.Conversion.From,
+			To:      se.Conversion.To,
+			Rate:    rate,
+			Updated: se.Conversion.Updated,
+		}
+	}
+
+	return e, nil
+}
@@ -1,18 +1,45 @@
 package services
 
 import (
+	"estimator/services/asset"
+	"estimator/services/blob"
+	"estimator/services/catalog"
+	"estimator/services/estimate"
+	"estimator/services/exchangerate"
 	"estimator/services/form"
+	"estimator/services/quote"
+	"estimator/services/submission"
 	"estimator/storage"
 )
 
 // Services defines the main business logic services.
 type Services struct {
-	Form *form.Service
+	Form         *form.Service
+	Submission   *submission.Service
+	Blob         *blob.Service
+	Asset        *asset.Service
+	Catalog      *catalog.Service
+	Estimate     *estimate.Service
+	ExchangeRate *exchangerate.Service
+	Quote        *quote.Service
 }
 
 // New creates a new services.
 func New(s *storage.Storage) *Services {
+	f := form.New(s)
+	c := catalog.New(s)
+	x := exchangerate.New(s)
+	e := estimate.New(s, f, c, x)
+	sub := submission.New(s, f, c)
+
 	return &Services{
-		Form: form.New(s),
+		Form:         f,
+		Submission:   sub,
+		Blob:         blob.New(s, f),
+		Asset:        asset.New(s),
+		Catalog:      c,
+		Estimate:     e,
+		ExchangeRate: x,
+		Quote:        quote.New(s, f, e, sub),
 	}
 }
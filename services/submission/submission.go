@@ -0,0 +1,431 @@
+package submission
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	catalogsvc "estimator/services/catalog"
+	"estimator/services/form"
+	"estimator/storage"
+	"estimator/storage/blob"
+	"estimator/storage/catalog"
+	"estimator/storage/submission"
+	"estimator/types"
+
+	"github.com/google/uuid"
+)
+
+// Service defines the submission service.
+type Service struct {
+	s       *storage.Storage
+	form    *form.Service
+	catalog *catalogsvc.Service
+}
+
+// New creates a new service.
+func New(s *storage.Storage, f *form.Service, c *catalogsvc.Service) *Service {
+	return &Service{
+		s:       s,
+		form:    f,
+		catalog: c,
+	}
+}
+
+// Create creates a new submission. The submission prefill parameters are
+// the query parameters of the page the form was rendered on.
+func (s *Service) Create(sub *types.Submission) (*types.Submission, error) {
+	// Get the published form being submitted.
+	f, err := s.form.GetPublished(sub.FormID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefill the form, keeping only the allow-listed parameters.
+	params := url.Values{}
+	for k, v := range sub.Prefill {
+		params.Set(k, v)
+	}
+	sub.Prefill = s.form.Prefill(f, params, sub.Referrer)
+
+	// Set hidden module answers, which are never taken from the client.
+	if sub.Answers == nil {
+		sub.Answers = map[string]interface{}{}
+	}
+	for _, module := range types.Flatten(f.Modules) {
+		if h, ok := module.(*types.Hidden); ok {
+			sub.Answers[h.Name] = h.Properties.Value
+		}
+	}
+
+	// Validate the answers.
+	if err := s.ValidateAnswers(f, sub.Answers); err != nil {
+		return nil, err
+	}
+
+	// Set ID, created and the form revision answered.
+	sub.ID = uuid.NewString()
+	sub.Created = time.Now().UTC()
+	sub.FormRevision = f.Revision
+
+	// Record signatures and consents.
+	blobIDs, err := s.sign(f, sub)
+	if err != nil {
+		return nil, err
+	}
+	s.consent(f, sub)
+
+	// Redeem the coupon, last, so a use is only counted for a submission
+	// that is saved.
+	if sub.Coupon != "" {
+		if err := s.redeem(f, sub); err != nil {
+			s.deleteBlobs(blobIDs)
+			return nil, err
+		}
+	}
+
+	// Map to storage type.
+	ss := &submission.Submission{
+		ID:           sub.ID,
+		FormID:       sub.FormID,
+		Answers:      sub.Answers,
+		Prefill:      sub.Prefill,
+		Referrer:     sub.Referrer,
+		IP:           sub.IP,
+		Coupon:       sub.Coupon,
+		Created:      sub.Created,
+		FormRevision: sub.FormRevision,
+	}
+
+	// Create in storage, giving the coupon use back and deleting the
+	// signature data if that fails.
+	ss, err = s.s.Submission.Create(ss)
+	if err != nil {
+		if sub.Coupon != "" {
+			if rerr := s.s.Coupon.Release(f.ID, strings.ToUpper(sub.Coupon)); rerr != nil {
+				// TODO: Use logger.
+				fmt.Printf("error releasing coupon: %v\n", rerr)
+			}
+		}
+		s.deleteBlobs(blobIDs)
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// GetByID gets a submission by the given ID.
+func (s *Service) GetByID(id string) (*types.Submission, error) {
+	// Try to pull this submission from the database.
+	dbs, err := s.s.Submission.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert interface to answers.
+	answers, ok := dbs.Answers.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid answers")
+	}
+
+	// Create a new Submission.
+	sub := &types.Submission{
+		ID:           dbs.ID,
+		FormID:       dbs.FormID,
+		FormRevision: dbs.FormRevision,
+		Answers:      answers,
+		Prefill:      dbs.Prefill,
+		Referrer:     dbs.Referrer,
+		IP:           dbs.IP,
+		Coupon:       dbs.Coupon,
+		Created:      dbs.Created,
+	}
+
+	return sub, nil
+}
+
+// ValidateAnswers validates the given answers against the modules of the
+// given form.
+func (s *Service) ValidateAnswers(f *types.Form, answers map[string]interface{}) error {
+	// Check for answers to unknown modules.
+	if err := checkUnknown(f.Modules, answers); err != nil {
+		return err
+	}
+
+	return s.validateModules(f.Modules, answers)
+}
+
+// ValidatePage validates the answers to a single page of the form with the
+// given ID, so a partial submission can be checked before moving on to the
+// next page. Answers to other pages are ignored.
+func (s *Service) ValidatePage(formID string, number int, answers map[string]interface{}) error {
+	// Get the published form.
+	f, err := s.form.GetPublished(formID)
+	if err != nil {
+		return err
+	}
+
+	// Get the page.
+	pages := f.Pages()
+	if number < 1 || number > len(pages) {
+		return errors.New("page could not be found")
+	}
+
+	// Check for answers to unknown modules.
+	if err := checkUnknown(f.Modules, answers); err != nil {
+		return err
+	}
+
+	return s.validateModules(pages[number-1].Modules, answers)
+}
+
+// checkUnknown checks the given answers only answer the given modules.
+func checkUnknown(modules []types.Module, answers map[string]interface{}) error {
+	// Map module names.
+	names := map[string]bool{}
+	for _, module := range types.Flatten(modules) {
+		names[module.GetName()] = true
+	}
+
+	// Check for answers to unknown modules.
+	for name := range answers {
+		if !names[name] {
+			return fmt.Errorf("unknown module %s", name)
+		}
+	}
+
+	return nil
+}
+
+// validateModules validates the answers to the given modules. Modules in
+// sections hidden by their visibility condition are not validated.
+func (s *Service) validateModules(modules []types.Module, answers map[string]interface{}) error {
+	// Loop through the modules.
+	for _, module := range types.VisibleModules(modules, answers) {
+		// Validate the answer if the module accepts one.
+		av, ok := module.(types.AnswerValidator)
+		if !ok {
+			continue
+		}
+		if err := av.ValidateAnswer(answers[module.GetName()]); err != nil {
+			return err
+		}
+
+		// Check uploaded files exist and are still allowed.
+		if fu, ok := module.(*types.FileUpload); ok {
+			if err := s.validateBlobs(fu, answers[module.GetName()]); err != nil {
+				return err
+			}
+		}
+
+		// Check product quantities are within the catalog limits.
+		if pl, ok := module.(*types.ProductList); ok {
+			if err := s.validateQuantities(pl, answers[module.GetName()]); err != nil {
+				return err
+			}
+		}
+
+		// Validate the child answers of each repeater item.
+		if r, ok := module.(*types.Repeater); ok {
+			if err := s.validateItems(r, answers[module.GetName()]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateItems validates the child answers of each item of a repeater
+// answer. Errors are prefixed with the repeater name and item index.
+func (s *Service) validateItems(r *types.Repeater, answer interface{}) error {
+	// Get the items.
+	items, err := r.Items(answer)
+	if err != nil {
+		return err
+	}
+
+	// Loop through the items.
+	for i, item := range items {
+		// Check for answers to unknown modules.
+		if err := checkUnknown(r.Modules, item); err != nil {
+			return fmt.Errorf("%s[%d] %v", r.Name, i, err)
+		}
+
+		// Validate the item answers.
+		if err := s.validateModules(r.Modules, item); err != nil {
+			return fmt.Errorf("%s[%d] %v", r.Name, i, err)
+		}
+	}
+
+	return nil
+}
+
+// redeem checks the submission coupon is valid, and counts a use of it
+// against its usage limit.
+func (s *Service) redeem(f *types.Form, sub *types.Submission) error {
+	// Get the coupon.
+	c, err := f.Pricing.Coupon(sub.Coupon, sub.Created)
+	if err != nil {
+		return err
+	}
+	sub.Coupon = c.Code
+
+	return s.s.Coupon.Redeem(f.ID, strings.ToUpper(c.Code), c.MaxUses)
+}
+
+// sign records the signer audit details on signature answers, and moves the
+// signature data to the blob store for modules that ask for it, returning
+// the IDs of the stored blobs. If it fails, the blobs already stored are
+// deleted.
+func (s *Service) sign(f *types.Form, sub *types.Submission) ([]string, error) {
+	blobIDs := []string{}
+	// Loop through the modules, including nested ones.
+	for _, module := range types.Flatten(f.Modules) {
+		// Get the signature answer.
+		sig, ok := module.(*types.Signature)
+		if !ok {
+			continue
+		}
+		m, ok := sub.Answers[sig.Name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// Record the audit details.
+		m["signed_at"] = sub.Created.Format(time.RFC3339)
+		m["ip"] = sub.IP
+
+		// Handle inline storage.
+		if sig.Properties.Storage != "blob" {
+			continue
+		}
+
+		// Get the signature data.
+		sb := &blob.Blob{
+			ID:      uuid.NewString(),
+			Created: sub.Created,
+		}
+		var data []byte
+		var err error
+		if image, ok := m["image"].(string); ok {
+			data, err = sig.DecodeImage(image)
+			sb.ContentType = "image/png"
+			sb.Filename = "signature.png"
+			delete(m, "image")
+		} else {
+			data, err = json.Marshal(m["strokes"])
+			sb.ContentType = "application/json"
+			sb.Filename = "signature.json"
+			delete(m, "strokes")
+		}
+		if err != nil {
+			s.deleteBlobs(blobIDs)
+			return nil, err
+		}
+
+		// Store the signature data.
+		sb, err = s.s.Blob.Put(sb, bytes.NewReader(data))
+		if err != nil {
+			s.deleteBlobs(blobIDs)
+			return nil, err
+		}
+		m["blob_id"] = sb.ID
+		blobIDs = append(blobIDs, sb.ID)
+	}
+
+	return blobIDs, nil
+}
+
+// deleteBlobs deletes the blobs with the given IDs, such as the signature
+// data of a submission that could not be saved.
+func (s *Service) deleteBlobs(ids []string) {
+	for _, id := range ids {
+		if err := s.s.Blob.Delete(id); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error deleting blob: %v\n", err)
+		}
+	}
+}
+
+// consent records exactly what was agreed to on accepted consent answers.
+func (s *Service) consent(f *types.Form, sub *types.Submission) {
+	// Loop through the modules, including nested ones.
+	for _, module := range types.Flatten(f.Modules) {
+		// Get the consent answer.
+		c, ok := module.(*types.Consent)
+		if !ok {
+			continue
+		}
+		m, ok := sub.Answers[c.Name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if accepted, _ := m["accepted"].(bool); !accepted {
+			continue
+		}
+
+		// Record the agreed content and audit details.
+		m["version"] = c.Properties.Version
+		m["hash"] = c.Properties.Hash
+		m["text"] = c.Properties.Text
+		m["terms_url"] = c.Properties.TermsURL
+		m["accepted_at"] = sub.Created.Format(time.RFC3339)
+		m["ip"] = sub.IP
+	}
+}
+
+// validateQuantities validates the quantities answered for a product list
+// module against the catalog item limits.
+func (s *Service) validateQuantities(pl *types.ProductList, answer interface{}) error {
+	for id, q := range pl.Quantities(answer) {
+		// Get the catalog item.
+		i, err := s.catalog.GetByID(id)
+		switch {
+		case err == catalog.ErrItemNotFound:
+			return fmt.Errorf("%s contains an item that could not be found", pl.Name)
+		case err != nil:
+			return err
+		}
+
+		// Check the limits.
+		if err := i.CheckQuantity(q); err != nil {
+			return fmt.Errorf("%s[%s] %v", pl.Name, id, err)
+		}
+	}
+
+	return nil
+}
+
+// validateBlobs validates the blob IDs answered for a file upload module.
+func (s *Service) validateBlobs(fu *types.FileUpload, answer interface{}) error {
+	ids, _ := answer.([]interface{})
+	for _, v := range ids {
+		id, _ := v.(string)
+
+		// Blob IDs are always UUIDs.
+		if _, err := uuid.Parse(id); err != nil {
+			return fmt.Errorf("%s contains an invalid file ID", fu.Name)
+		}
+
+		// Get the blob.
+		b, err := s.s.Blob.Stat(id)
+		switch {
+		case err == blob.ErrBlobNotFound:
+			return fmt.Errorf("%s contains a file that could not be found", fu.Name)
+		case err != nil:
+			return err
+		}
+
+		// Check the content type and size.
+		if !fu.AllowsType(b.ContentType) || b.Size > int64(fu.Properties.MaxSize) {
+			return fmt.Errorf("%s contains a file that is not allowed", fu.Name)
+		}
+	}
+
+	return nil
+}
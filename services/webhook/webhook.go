@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"estimator/types"
+)
+
+// timeout defines how long a webhook request may take.
+const timeout = 10 * time.Second
+
+// Service defines the webhook service.
+type Service struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// Event defines the webhook event payload.
+type Event struct {
+	Type  string    `json:"type"`
+	At    time.Time `json:"at"`
+	Quote Quote     `json:"quote"`
+}
+
+// Quote defines the quote of a webhook event payload.
+type Quote struct {
+	ID        string      `json:"id"`
+	Number    string      `json:"number"`
+	FormID    string      `json:"form_id"`
+	Status    string      `json:"status"`
+	Total     types.Money `json:"total"`
+	Currency  string      `json:"currency"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	Response  *Response   `json:"response"`
+}
+
+// Response defines the customer response of a webhook event quote.
+type Response struct {
+	Status string    `json:"status"`
+	Name   string    `json:"name"`
+	Signed bool      `json:"signed"`
+	At     time.Time `json:"at"`
+}
+
+// New creates a new service which posts events to the given URL. Requests
+// are signed with the secret, when set, in the X-Estimator-Signature header
+// as the hex encoded HMAC-SHA256 of the body.
+func New(url, secret string) *Service {
+	return &Service{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// QuoteEvent posts a quote event in the background, so it can be used as a
+// quote service subscriber.
+func (s *Service) QuoteEvent(e *types.QuoteEvent) {
+	// Map to the event payload.
+	q := e.Quote
+	ev := &Event{
+		Type: e.Type,
+		At:   e.At,
+		Quote: Quote{
+			ID:        q.ID,
+			Number:    q.Number,
+			FormID:    q.Form.ID,
+			Status:    q.Status,
+			Total:     q.Estimate.Total,
+			Currency:  q.Estimate.Total.Currency,
+			ExpiresAt: q.ExpiresAt,
+		},
+	}
+	if q.Response != nil {
+		ev.Quote.Response = &Response{
+			Status: q.Response.Status,
+			Name:   q.Response.Name,
+			Signed: q.Response.Signature != nil,
+			At:     q.Response.At,
+		}
+	}
+
+	go func() {
+		if err := s.Post(ev); err != nil {
+			// TODO: Use logger.
+			fmt.Printf("error posting webhook: %v\n", err)
+		}
+	}()
+}
+
+// Post posts the given event.
+func (s *Service) Post(ev *Event) error {
+	// Encode the payload.
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	// Create the request.
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write(b)
+		req.Header.Set("X-Estimator-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	// Send the request.
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package blob
+
+import (
+	"io"
+	"time"
+)
+
+// Store defines the blob store interface.
+type Store interface {
+	Put(b *Blob, r io.Reader) (*Blob, error)
+	Get(id string) (*Blob, io.ReadCloser, error)
+	Stat(id string) (*Blob, error)
+	Delete(id string) error
+}
+
+// Blob defines a blob.
+type Blob struct {
+	ID          string
+	ContentType string
+	Filename    string
+	Size        int64
+	Created     time.Time
+}
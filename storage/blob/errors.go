@@ -0,0 +1,8 @@
+package blob
+
+import "errors"
+
+var (
+	// ErrBlobNotFound is returned when a blob could not be found.
+	ErrBlobNotFound = errors.New("blob could not be found")
+)
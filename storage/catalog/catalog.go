@@ -0,0 +1,25 @@
+package catalog
+
+// Database defines the catalog database interface.
+type Database interface {
+	Create(i *Item) (*Item, error)
+	Get(limit, offset int) ([]*Item, error)
+	GetByID(id string) (*Item, error)
+	UpdateByID(id string, i *Item) (*Item, error)
+	DeleteByID(id string) error
+}
+
+// Item defines a catalog item. Prices are exact decimal strings.
+type Item struct {
+	ID          string
+	Name        string
+	SKU         string
+	Description string
+	UnitPrice   string
+	MinPrice    string
+	MaxPrice    string
+	Unit        string
+	Image       string
+	MinQuantity int
+	MaxQuantity int
+}
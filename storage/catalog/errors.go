@@ -0,0 +1,12 @@
+package catalog
+
+import "errors"
+
+var (
+	// ErrItemNotFound is returned when a catalog item could not be found.
+	ErrItemNotFound = errors.New("catalog item could not be found")
+
+	// ErrSKUExists is returned when a catalog item with the same SKU
+	// already exists.
+	ErrSKUExists = errors.New("catalog item SKU already exists")
+)
@@ -0,0 +1,9 @@
+package coupon
+
+// Database defines the coupon database interface, which counts the
+// redemptions of form coupons.
+type Database interface {
+	GetUses(formID, code string) (int, error)
+	Redeem(formID, code string, maxUses int) error
+	Release(formID, code string) error
+}
@@ -0,0 +1,9 @@
+package coupon
+
+import "errors"
+
+var (
+	// ErrUsageLimitReached is returned when a coupon has already been
+	// redeemed the maximum number of times.
+	ErrUsageLimitReached = errors.New("coupon usage limit reached")
+)
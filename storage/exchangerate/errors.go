@@ -0,0 +1,8 @@
+package exchangerate
+
+import "errors"
+
+var (
+	// ErrRateNotFound is returned when an exchange rate could not be found.
+	ErrRateNotFound = errors.New("exchange rate could not be found")
+)
@@ -0,0 +1,19 @@
+package exchangerate
+
+import "time"
+
+// Database defines the exchange rate database interface.
+type Database interface {
+	Get() ([]*Rate, error)
+	GetByPair(base, quote string) (*Rate, error)
+	Upsert(r *Rate) error
+	UpsertAll(rs []*Rate) error
+}
+
+// Rate defines an exchange rate. The rate is an exact decimal string.
+type Rate struct {
+	Base    string
+	Quote   string
+	Rate    string
+	Updated time.Time
+}
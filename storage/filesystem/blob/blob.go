@@ -0,0 +1,127 @@
+package blob
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"estimator/storage/blob"
+)
+
+// Store defines the store.
+type Store struct {
+	dir string
+}
+
+// New creates a new store which keeps blobs in the given directory.
+func New(dir string) *Store {
+	return &Store{
+		dir: dir,
+	}
+}
+
+// dataPath returns the path of the blob data file.
+func (s *Store) dataPath(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+// metaPath returns the path of the blob metadata file.
+func (s *Store) metaPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// Put streams a new blob to the filesystem.
+func (s *Store) Put(b *blob.Blob, r io.Reader) (*blob.Blob, error) {
+	// Make sure the directory exists.
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return nil, err
+	}
+
+	// Create the data file.
+	file, err := os.OpenFile(s.dataPath(b.ID), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stream the data.
+	size, err := io.Copy(file, r)
+	if err != nil {
+		file.Close()
+		os.Remove(s.dataPath(b.ID))
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(s.dataPath(b.ID))
+		return nil, err
+	}
+	b.Size = size
+
+	// Write the metadata.
+	meta, err := json.Marshal(b)
+	if err != nil {
+		os.Remove(s.dataPath(b.ID))
+		return nil, err
+	}
+	if err := os.WriteFile(s.metaPath(b.ID), meta, 0600); err != nil {
+		os.Remove(s.dataPath(b.ID))
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Get gets a blob and its data by the given ID. The caller must close the
+// returned reader.
+func (s *Store) Get(id string) (*blob.Blob, io.ReadCloser, error) {
+	// Get the metadata.
+	b, err := s.Stat(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Open the data file.
+	file, err := os.Open(s.dataPath(id))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, nil, blob.ErrBlobNotFound
+	case err != nil:
+		return nil, nil, err
+	}
+
+	return b, file, nil
+}
+
+// Stat gets a blob by the given ID without its data.
+func (s *Store) Stat(id string) (*blob.Blob, error) {
+	// Read the metadata.
+	meta, err := os.ReadFile(s.metaPath(id))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, blob.ErrBlobNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	// Map to storage blob type.
+	b := &blob.Blob{}
+	if err := json.Unmarshal(meta, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Delete deletes a blob by the given ID.
+func (s *Store) Delete(id string) error {
+	if err := os.Remove(s.metaPath(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(s.dataPath(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -5,4 +5,8 @@ import "errors"
 var (
 	// ErrFormNotFound is returned when a form could not be found.
 	ErrFormNotFound = errors.New("form could not be found")
+
+	// ErrVersionConflict is returned when a form was updated by someone
+	// else since the version being updated.
+	ErrVersionConflict = errors.New("form version conflict")
 )
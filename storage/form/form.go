@@ -1,14 +1,28 @@
 package form
 
-// Database defines the form database interface.
+import "estimator/storage/formrevision"
+
+// Database defines the form database interface. CreateWithRevision and
+// UpdateWithRevision save the form along with the given revision, so
+// neither is saved without the other. UpdateWithRevision and Publish only
+// update the form when its version is still the given version, and
+// increment the version.
 type Database interface {
-	Create(f *Form) (*Form, error)
+	CreateWithRevision(f *Form, r *formrevision.Revision) (*Form, error)
 	GetByID(id string) (*Form, error)
-	UpdateByID(id string, f *Form) (*Form, error)
+	UpdateWithRevision(id string, f *Form, r *formrevision.Revision) (*Form, error)
+	Publish(id string, revision, version int) error
 }
 
-// Form defines a form.
+// Form defines a form. The modules and pricing settings are the working
+// draft, and Revision is its revision number. PublishedRevision is the
+// number of the revision customers see. Version is incremented on every
+// update.
 type Form struct {
-	ID      string
-	Modules interface{}
+	ID                string
+	Modules           interface{}
+	Pricing           interface{}
+	Revision          int
+	Version           int
+	PublishedRevision int
 }
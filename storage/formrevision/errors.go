@@ -0,0 +1,9 @@
+package formrevision
+
+import "errors"
+
+var (
+	// ErrRevisionNotFound is returned when a form revision could not be
+	// found.
+	ErrRevisionNotFound = errors.New("form revision could not be found")
+)
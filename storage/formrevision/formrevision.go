@@ -0,0 +1,21 @@
+package formrevision
+
+import "time"
+
+// Database defines the form revision database interface. Revisions are
+// created along with their form, see the form database.
+type Database interface {
+	Get(formID string, limit, offset int) ([]*Revision, error)
+	GetByNumber(formID string, number int) (*Revision, error)
+}
+
+// Revision defines a form revision, the form definition as it was saved by
+// the author.
+type Revision struct {
+	FormID  string
+	Number  int
+	Author  string
+	Modules interface{}
+	Pricing interface{}
+	Created time.Time
+}
@@ -0,0 +1,181 @@
+package catalog
+
+import (
+	"database/sql"
+	"errors"
+
+	"estimator/storage/catalog"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// errDuplicateEntry defines the MySQL error number for a duplicate key.
+const errDuplicateEntry = 1062
+
+// Database defines the database.
+type Database struct {
+	db *sql.DB
+}
+
+// New creates a new database.
+func New(db *sql.DB) *Database {
+	return &Database{
+		db: db,
+	}
+}
+
+const (
+	// stmtInsert defines the SQL statement to
+	// insert a new catalog item into the database.
+	stmtInsert = `
+INSERT INTO catalog_items (id, name, sku, description, unit_price, min_price, max_price, unit, image, min_quantity, max_quantity)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+	// stmtGet defines the SQL statement to
+	// get a page of catalog items from the database.
+	stmtGet = `
+SELECT * FROM catalog_items
+ORDER BY name, id
+LIMIT ? OFFSET ?
+`
+
+	// stmtGetByID defines the SQL statement to
+	// get a catalog item from the database.
+	stmtGetByID = `
+SELECT * FROM catalog_items
+WHERE id=?
+`
+
+	// stmtUpdateByID defines the SQL statement
+	// to update a catalog item by the given ID.
+	stmtUpdateByID = `
+UPDATE catalog_items
+SET name=?, sku=?, description=?, unit_price=?, min_price=?, max_price=?, unit=?, image=?, min_quantity=?, max_quantity=?
+WHERE id=?
+`
+
+	// stmtDeleteByID defines the SQL statement
+	// to delete a catalog item by the given ID.
+	stmtDeleteByID = `
+DELETE FROM catalog_items
+WHERE id=?
+`
+)
+
+// scanner defines the interface shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanItem maps columns to a catalog item.
+func scanItem(s scanner) (*catalog.Item, error) {
+	i := &catalog.Item{}
+	err := s.Scan(&i.ID, &i.Name, &i.SKU, &i.Description, &i.UnitPrice, &i.MinPrice, &i.MaxPrice, &i.Unit, &i.Image, &i.MinQuantity, &i.MaxQuantity)
+	return i, err
+}
+
+// isDuplicate checks if the given error is a MySQL duplicate key error.
+func isDuplicate(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && me.Number == errDuplicateEntry
+}
+
+// Create creates a new catalog item.
+func (db *Database) Create(i *catalog.Item) (*catalog.Item, error) {
+	// Execute the query.
+	_, err := db.db.Exec(stmtInsert, i.ID, i.Name, i.SKU, i.Description, i.UnitPrice, i.MinPrice, i.MaxPrice, i.Unit, i.Image, i.MinQuantity, i.MaxQuantity)
+	switch {
+	case isDuplicate(err):
+		return nil, catalog.ErrSKUExists
+	case err != nil:
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Get gets a page of catalog items.
+func (db *Database) Get(limit, offset int) ([]*catalog.Item, error) {
+	// Execute the query.
+	rows, err := db.db.Query(stmtGet, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Map rows to catalog items.
+	items := []*catalog.Item{}
+	for rows.Next() {
+		i, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetByID gets a catalog item by the given ID.
+func (db *Database) GetByID(id string) (*catalog.Item, error) {
+	// Execute the query.
+	row := db.db.QueryRow(stmtGetByID, id)
+
+	// Map columns to catalog item.
+	i, err := scanItem(row)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, catalog.ErrItemNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// UpdateByID updates a catalog item by the given ID.
+func (db *Database) UpdateByID(id string, i *catalog.Item) (*catalog.Item, error) {
+	// Execute the query.
+	res, err := db.db.Exec(stmtUpdateByID, i.Name, i.SKU, i.Description, i.UnitPrice, i.MinPrice, i.MaxPrice, i.Unit, i.Image, i.MinQuantity, i.MaxQuantity, id)
+	switch {
+	case isDuplicate(err):
+		return nil, catalog.ErrSKUExists
+	case err != nil:
+		return nil, err
+	}
+
+	// Check the item exists, as unchanged rows are not counted as affected.
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		if _, err := db.GetByID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	i.ID = id
+
+	return i, nil
+}
+
+// DeleteByID deletes a catalog item by the given ID.
+func (db *Database) DeleteByID(id string) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtDeleteByID, id)
+	if err != nil {
+		return err
+	}
+
+	// Check the item existed.
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return catalog.ErrItemNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package coupon
+
+import (
+	"database/sql"
+	"math"
+
+	"estimator/storage/coupon"
+)
+
+// Database defines the database.
+type Database struct {
+	db *sql.DB
+}
+
+// New creates a new database.
+func New(db *sql.DB) *Database {
+	return &Database{
+		db: db,
+	}
+}
+
+const (
+	// stmtGetUses defines the SQL statement to
+	// get the number of uses of a coupon from the database.
+	stmtGetUses = `
+SELECT uses FROM coupon_redemptions
+WHERE form_id=? AND code=?
+`
+
+	// stmtRedeem defines the SQL statement to
+	// count a coupon use, unless the usage limit is reached.
+	stmtRedeem = `
+INSERT INTO coupon_redemptions (form_id, code, uses)
+VALUES (?, ?, 1)
+ON DUPLICATE KEY UPDATE uses=IF(uses < ?, uses + 1, uses)
+`
+
+	// stmtRelease defines the SQL statement to
+	// give back a coupon use.
+	stmtRelease = `
+UPDATE coupon_redemptions SET uses=uses - 1
+WHERE form_id=? AND code=? AND uses > 0
+`
+)
+
+// GetUses gets the number of times the coupon with the given code has been
+// redeemed for the given form.
+func (db *Database) GetUses(formID, code string) (int, error) {
+	var uses int
+
+	// Execute the query.
+	err := db.db.QueryRow(stmtGetUses, formID, code).Scan(&uses)
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, nil
+	case err != nil:
+		return 0, err
+	}
+
+	return uses, nil
+}
+
+// Redeem counts a use of the coupon with the given code for the given form.
+// The check and the count happen in a single statement, so concurrent
+// redemptions can't exceed the maximum uses. A maxUses of zero means no
+// limit.
+func (db *Database) Redeem(formID, code string, maxUses int) error {
+	if maxUses == 0 {
+		maxUses = math.MaxInt32
+	}
+
+	// Execute the query.
+	res, err := db.db.Exec(stmtRedeem, formID, code, maxUses)
+	if err != nil {
+		return err
+	}
+
+	// Check the use was counted, as unchanged rows are not counted as
+	// affected.
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return coupon.ErrUsageLimitReached
+	}
+
+	return nil
+}
+
+// Release gives back a use of the coupon with the given code for the given
+// form, such as when the submission it was redeemed for could not be saved.
+func (db *Database) Release(formID, code string) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtRelease, formID, code)
+	return err
+}
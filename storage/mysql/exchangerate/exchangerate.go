@@ -0,0 +1,126 @@
+package exchangerate
+
+import (
+	"database/sql"
+
+	"estimator/storage/exchangerate"
+)
+
+// Database defines the database.
+type Database struct {
+	db *sql.DB
+}
+
+// New creates a new database.
+func New(db *sql.DB) *Database {
+	return &Database{
+		db: db,
+	}
+}
+
+const (
+	// stmtGet defines the SQL statement to
+	// get all exchange rates from the database.
+	stmtGet = `
+SELECT * FROM exchange_rates
+ORDER BY base, quote
+`
+
+	// stmtGetByPair defines the SQL statement to
+	// get an exchange rate from the database.
+	stmtGetByPair = `
+SELECT * FROM exchange_rates
+WHERE base=? AND quote=?
+`
+
+	// stmtUpsert defines the SQL statement to
+	// insert or replace an exchange rate in the database.
+	stmtUpsert = `
+INSERT INTO exchange_rates (base, quote, rate, updated)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE rate=VALUES(rate), updated=VALUES(updated)
+`
+)
+
+// scanner defines the interface shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRate maps columns to an exchange rate.
+func scanRate(s scanner) (*exchangerate.Rate, error) {
+	r := &exchangerate.Rate{}
+	err := s.Scan(&r.Base, &r.Quote, &r.Rate, &r.Updated)
+	return r, err
+}
+
+// Get gets all exchange rates.
+func (db *Database) Get() ([]*exchangerate.Rate, error) {
+	// Execute the query.
+	rows, err := db.db.Query(stmtGet)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Map rows to exchange rates.
+	rates := []*exchangerate.Rate{}
+	for rows.Next() {
+		r, err := scanRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// GetByPair gets the exchange rate from the base to the quote currency.
+func (db *Database) GetByPair(base, quote string) (*exchangerate.Rate, error) {
+	// Execute the query.
+	row := db.db.QueryRow(stmtGetByPair, base, quote)
+
+	// Map columns to exchange rate.
+	r, err := scanRate(row)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, exchangerate.ErrRateNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Upsert creates the exchange rate, or replaces it if the currency pair
+// already exists.
+func (db *Database) Upsert(r *exchangerate.Rate) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtUpsert, r.Base, r.Quote, r.Rate, r.Updated)
+	return err
+}
+
+// UpsertAll creates or replaces the given exchange rates in a single
+// transaction, so either all of them are saved or none are.
+func (db *Database) UpsertAll(rs []*exchangerate.Rate) error {
+	// Begin the transaction.
+	tx, err := db.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Execute the queries.
+	for _, r := range rs {
+		if _, err := tx.Exec(stmtUpsert, r.Base, r.Quote, r.Rate, r.Updated); err != nil {
+			return err
+		}
+	}
+
+	// Commit the transaction.
+	return tx.Commit()
+}
@@ -1,11 +1,13 @@
 package form
 
 import (
+	"bytes"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 
 	"estimator/storage/form"
+	"estimator/storage/formrevision"
 )
 
 // Database defines the database.
@@ -24,8 +26,15 @@ const (
 	// stmtInsert defines the SQL statement to
 	// insert a new form into the database.
 	stmtInsert = `
-INSERT INTO forms (id, modules)
-VALUES (?, ?)
+INSERT INTO forms (id, modules, pricing, revision, version, published_revision)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+	// stmtInsertRevision defines the SQL statement to
+	// insert a new form revision into the database.
+	stmtInsertRevision = `
+INSERT INTO form_revisions (form_id, number, author, modules, pricing, created)
+VALUES (?, ?, ?, ?, ?, ?)
 `
 
 	// stmtGetByID defines the SQL statement to
@@ -35,19 +44,31 @@ SELECT * FROM forms
 WHERE id=?
 `
 
-	// stmtUpdateByID defines the SQL statement
-	// to update a form by the given ID.
+	// stmtUpdateByID defines the SQL statement to update a
+	// form by the given ID, if its version has not changed.
 	stmtUpdateByID = `
 UPDATE forms
-SET modules=?
-WHERE id=?
+SET modules=?, pricing=?, revision=?, version=version+1
+WHERE id=? AND version=?
+`
+
+	// stmtPublish defines the SQL statement to publish a
+	// revision of a form, if its version has not changed.
+	stmtPublish = `
+UPDATE forms
+SET published_revision=?, version=version+1
+WHERE id=? AND version=?
 `
 )
 
 // Form defines a form.
 type Form struct {
-	ID      string
-	Modules Modules
+	ID                string
+	Modules           Modules
+	Pricing           Pricing
+	Revision          int
+	Version           int
+	PublishedRevision int
 }
 
 // Modules defines form modules.
@@ -68,21 +89,68 @@ func (m Modules) Value() (driver.Value, error) {
 // Scan implements the Scanner interface.
 func (m *Modules) Scan(src any) error {
 	val := src.([]uint8)
-	return json.Unmarshal(val, &m.Data)
+	dec := json.NewDecoder(bytes.NewReader(val))
+	dec.UseNumber()
+	return dec.Decode(&m.Data)
+}
+
+// Pricing defines form pricing settings.
+type Pricing struct {
+	Data interface{}
 }
 
-// Create creates a new form.
-func (db *Database) Create(f *form.Form) (*form.Form, error) {
+// Value implements the driver interface.
+func (p Pricing) Value() (driver.Value, error) {
+	b, err := json.Marshal(p.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (p *Pricing) Scan(src any) error {
+	val := src.([]uint8)
+	dec := json.NewDecoder(bytes.NewReader(val))
+	dec.UseNumber()
+	return dec.Decode(&p.Data)
+}
+
+// CreateWithRevision creates a new form along with the given revision, in a
+// single transaction.
+func (db *Database) CreateWithRevision(f *form.Form, r *formrevision.Revision) (*form.Form, error) {
 	// Map to local Form type.
 	lf := &Form{
 		ID: f.ID,
 		Modules: Modules{
 			Data: f.Modules,
 		},
+		Pricing: Pricing{
+			Data: f.Pricing,
+		},
+		Revision:          f.Revision,
+		Version:           f.Version,
+		PublishedRevision: f.PublishedRevision,
 	}
 
-	// Execute the query.
-	if _, err := db.db.Exec(stmtInsert, lf.ID, lf.Modules); err != nil {
+	// Begin the transaction.
+	tx, err := db.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Execute the queries.
+	if _, err := tx.Exec(stmtInsert, lf.ID, lf.Modules, lf.Pricing, lf.Revision, lf.Version, lf.PublishedRevision); err != nil {
+		return nil, err
+	}
+	if err := insertRevision(tx, r); err != nil {
+		return nil, err
+	}
+
+	// Commit the transaction.
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -94,13 +162,14 @@ func (db *Database) GetByID(id string) (*form.Form, error) {
 	// Create a new Form.
 	f := &Form{
 		Modules: Modules{},
+		Pricing: Pricing{},
 	}
 
 	// Execute the query.
 	row := db.db.QueryRow(stmtGetByID, id)
 
 	// Map columns to form.
-	err := row.Scan(&f.ID, &f.Modules)
+	err := row.Scan(&f.ID, &f.Modules, &f.Pricing, &f.Revision, &f.Version, &f.PublishedRevision)
 	switch {
 	case err == sql.ErrNoRows:
 		return nil, form.ErrFormNotFound
@@ -110,27 +179,102 @@ func (db *Database) GetByID(id string) (*form.Form, error) {
 
 	// Map to storage form type.
 	gf := &form.Form{
-		ID:      f.ID,
-		Modules: f.Modules.Data,
+		ID:                f.ID,
+		Modules:           f.Modules.Data,
+		Pricing:           f.Pricing.Data,
+		Revision:          f.Revision,
+		Version:           f.Version,
+		PublishedRevision: f.PublishedRevision,
 	}
 
 	return gf, nil
 }
 
-// UpdateByID a form by the given ID.
-func (db *Database) UpdateByID(id string, f *form.Form) (*form.Form, error) {
+// UpdateWithRevision updates a form by the given ID along with creating the
+// given revision, in a single transaction. The check of the version and the
+// update happen in a single statement, so concurrent updates of the same
+// version can't overwrite each other.
+func (db *Database) UpdateWithRevision(id string, f *form.Form, r *formrevision.Revision) (*form.Form, error) {
 	// Map to local Form type.
 	lf := &Form{
 		ID: id,
 		Modules: Modules{
 			Data: f.Modules,
 		},
+		Pricing: Pricing{
+			Data: f.Pricing,
+		},
+		Revision: f.Revision,
+		Version:  f.Version,
 	}
 
-	// Execute the query.
-	if _, err := db.db.Exec(stmtUpdateByID, lf.Modules, lf.ID); err != nil {
+	// Begin the transaction.
+	tx, err := db.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Execute the update.
+	res, err := tx.Exec(stmtUpdateByID, lf.Modules, lf.Pricing, lf.Revision, lf.ID, lf.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the form was updated.
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		tx.Rollback()
+		if _, err := db.GetByID(id); err != nil {
+			return nil, err
+		}
+		return nil, form.ErrVersionConflict
+	}
+
+	// Record the revision.
+	if err := insertRevision(tx, r); err != nil {
 		return nil, err
 	}
 
+	// Commit the transaction.
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	f.Version++
+
 	return f, nil
 }
+
+// insertRevision inserts the given form revision within the given
+// transaction.
+func insertRevision(tx *sql.Tx, r *formrevision.Revision) error {
+	_, err := tx.Exec(stmtInsertRevision, r.FormID, r.Number, r.Author, Modules{Data: r.Modules}, Pricing{Data: r.Pricing}, r.Created)
+	return err
+}
+
+// Publish publishes the revision with the given number of the form with the
+// given ID, if the version of the form is still the given version.
+func (db *Database) Publish(id string, revision, version int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtPublish, revision, id, version)
+	if err != nil {
+		return err
+	}
+
+	// Check the form was updated.
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if _, err := db.GetByID(id); err != nil {
+			return err
+		}
+		return form.ErrVersionConflict
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package formrevision
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"estimator/storage/formrevision"
+)
+
+// Database defines the database.
+type Database struct {
+	db *sql.DB
+}
+
+// New creates a new database.
+func New(db *sql.DB) *Database {
+	return &Database{
+		db: db,
+	}
+}
+
+const (
+	// stmtGet defines the SQL statement to get a
+	// page of form revisions, newest first.
+	stmtGet = `
+SELECT * FROM form_revisions
+WHERE form_id=?
+ORDER BY number DESC
+LIMIT ? OFFSET ?
+`
+
+	// stmtGetByNumber defines the SQL statement to
+	// get a form revision by its number.
+	stmtGetByNumber = `
+SELECT * FROM form_revisions
+WHERE form_id=? AND number=?
+`
+)
+
+// Revision defines a form revision.
+type Revision struct {
+	FormID  string
+	Number  int
+	Author  string
+	Modules JSON
+	Pricing JSON
+	Created time.Time
+}
+
+// JSON defines a JSON column.
+type JSON struct {
+	Data interface{}
+}
+
+// Value implements the driver interface.
+func (j JSON) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (j *JSON) Scan(src any) error {
+	val := src.([]uint8)
+	dec := json.NewDecoder(bytes.NewReader(val))
+	dec.UseNumber()
+	return dec.Decode(&j.Data)
+}
+
+// scanner defines a row that can be scanned, such as *sql.Row or *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRevision maps the columns of a row to a form revision.
+func scanRevision(s scanner) (*formrevision.Revision, error) {
+	r := &Revision{}
+	err := s.Scan(&r.FormID, &r.Number, &r.Author, &r.Modules, &r.Pricing, &r.Created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &formrevision.Revision{
+		FormID:  r.FormID,
+		Number:  r.Number,
+		Author:  r.Author,
+		Modules: r.Modules.Data,
+		Pricing: r.Pricing.Data,
+		Created: r.Created,
+	}, nil
+}
+
+// Get gets a page of revisions of the form with the given ID, newest first.
+func (db *Database) Get(formID string, limit, offset int) ([]*formrevision.Revision, error) {
+	// Execute the query.
+	rows, err := db.db.Query(stmtGet, formID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Map rows to revisions.
+	revisions := []*formrevision.Revision{}
+	for rows.Next() {
+		r, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetByNumber gets a revision of the form with the given ID by its number.
+func (db *Database) GetByNumber(formID string, number int) (*formrevision.Revision, error) {
+	// Execute the query.
+	row := db.db.QueryRow(stmtGetByNumber, formID, number)
+
+	// Map columns to revision.
+	r, err := scanRevision(row)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, formrevision.ErrRevisionNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -4,14 +4,26 @@ import (
 	"database/sql"
 
 	"estimator/storage"
+	"estimator/storage/mysql/catalog"
+	"estimator/storage/mysql/coupon"
+	"estimator/storage/mysql/exchangerate"
 	"estimator/storage/mysql/form"
+	"estimator/storage/mysql/formrevision"
+	"estimator/storage/mysql/quote"
+	"estimator/storage/mysql/submission"
 )
 
 // New returns a new storage implementation that uses MYSQL as the backend
 // database.
 func New(db *sql.DB) *storage.Storage {
 	store := &storage.Storage{
-		Form: form.New(db),
+		Form:         form.New(db),
+		FormRevision: formrevision.New(db),
+		Submission:   submission.New(db),
+		Catalog:      catalog.New(db),
+		Coupon:       coupon.New(db),
+		ExchangeRate: exchangerate.New(db),
+		Quote:        quote.New(db),
 	}
 
 	return store
@@ -0,0 +1,416 @@
+package quote
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"estimator/storage/quote"
+)
+
+// Database defines the database.
+type Database struct {
+	db *sql.DB
+}
+
+// New creates a new database.
+func New(db *sql.DB) *Database {
+	return &Database{
+		db: db,
+	}
+}
+
+const (
+	// stmtInsert defines the SQL statement to
+	// insert a new quote into the database.
+	stmtInsert = `
+INSERT INTO quotes (id, number, form_id, form_modules, form_pricing, answers, estimate, status, expires_at, created, updated, token, require_signature, response, form_revision, form_version)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+	// stmtGet defines the SQL statement to get a page of quotes from the
+	// database, optionally filtered by form ID and status.
+	stmtGet = `
+SELECT * FROM quotes
+WHERE (?='' OR form_id=?) AND (?='' OR status=?)
+ORDER BY created DESC, id
+LIMIT ? OFFSET ?
+`
+
+	// stmtGetByID defines the SQL statement to
+	// get a quote from the database.
+	stmtGetByID = `
+SELECT * FROM quotes
+WHERE id=?
+`
+
+	// stmtGetByToken defines the SQL statement to
+	// get a quote by its public token from the database.
+	stmtGetByToken = `
+SELECT * FROM quotes
+WHERE token=?
+`
+
+	// stmtUpdateStatus defines the SQL statement to
+	// update the status of a quote, if it has not changed.
+	stmtUpdateStatus = `
+UPDATE quotes
+SET status=?, updated=?
+WHERE id=? AND status=?
+`
+
+	// stmtRespond defines the SQL statement to record a customer
+	// response to a quote, if its status has not changed and it
+	// has not expired.
+	stmtRespond = `
+UPDATE quotes
+SET status=?, response=?, updated=?
+WHERE id=? AND status=? AND expires_at>?
+`
+
+	// stmtGetExpired defines the SQL statement to get
+	// the open quotes past their expiry date.
+	stmtGetExpired = `
+SELECT * FROM quotes
+WHERE status IN ('draft', 'sent') AND expires_at<=?
+ORDER BY expires_at
+`
+
+	// stmtNextNumber defines the SQL statement to
+	// count a quote for the given year, returning the count.
+	stmtNextNumber = `
+INSERT INTO quote_numbers (year, last)
+VALUES (?, LAST_INSERT_ID(1))
+ON DUPLICATE KEY UPDATE last=LAST_INSERT_ID(last + 1)
+`
+)
+
+// Quote defines a quote.
+type Quote struct {
+	ID               string
+	Number           string
+	FormID           string
+	FormModules      FormJSON
+	FormPricing      FormJSON
+	Answers          JSON
+	Estimate         Estimate
+	Status           string
+	ExpiresAt        time.Time
+	Created          time.Time
+	Updated          time.Time
+	Token            string
+	RequireSignature bool
+	Response         Response
+	FormRevision     int
+	FormVersion      int
+}
+
+// JSON defines a JSON column.
+type JSON struct {
+	Data interface{}
+}
+
+// Value implements the driver interface.
+func (j JSON) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (j *JSON) Scan(src any) error {
+	val := src.([]uint8)
+	return json.Unmarshal(val, &j.Data)
+}
+
+// FormJSON defines a JSON column holding form modules or pricing settings,
+// decoded with numbers kept as written so prices are exact.
+type FormJSON struct {
+	Data interface{}
+}
+
+// Value implements the driver interface.
+func (j FormJSON) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (j *FormJSON) Scan(src any) error {
+	val := src.([]uint8)
+	dec := json.NewDecoder(bytes.NewReader(val))
+	dec.UseNumber()
+	return dec.Decode(&j.Data)
+}
+
+// Estimate defines a quote estimate.
+type Estimate struct {
+	Data *quote.Estimate
+}
+
+// Value implements the driver interface.
+func (e Estimate) Value() (driver.Value, error) {
+	b, err := json.Marshal(e.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (e *Estimate) Scan(src any) error {
+	val := src.([]uint8)
+	return json.Unmarshal(val, &e.Data)
+}
+
+// Response defines a quote response.
+type Response struct {
+	Data *quote.Response
+}
+
+// Value implements the driver interface.
+func (r Response) Value() (driver.Value, error) {
+	b, err := json.Marshal(r.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (r *Response) Scan(src any) error {
+	val := src.([]uint8)
+	return json.Unmarshal(val, &r.Data)
+}
+
+// scanner defines the interface shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanQuote maps columns to a quote.
+func scanQuote(s scanner) (*quote.Quote, error) {
+	q := &Quote{}
+	err := s.Scan(&q.ID, &q.Number, &q.FormID, &q.FormModules, &q.FormPricing, &q.Answers, &q.Estimate, &q.Status, &q.ExpiresAt, &q.Created, &q.Updated, &q.Token, &q.RequireSignature, &q.Response, &q.FormRevision, &q.FormVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map to storage quote type.
+	gq := &quote.Quote{
+		ID:               q.ID,
+		Number:           q.Number,
+		Token:            q.Token,
+		FormID:           q.FormID,
+		FormRevision:     q.FormRevision,
+		FormVersion:      q.FormVersion,
+		FormModules:      q.FormModules.Data,
+		FormPricing:      q.FormPricing.Data,
+		Answers:          q.Answers.Data,
+		Estimate:         q.Estimate.Data,
+		Status:           q.Status,
+		ExpiresAt:        q.ExpiresAt,
+		RequireSignature: q.RequireSignature,
+		Response:         q.Response.Data,
+		Created:          q.Created,
+		Updated:          q.Updated,
+	}
+
+	return gq, nil
+}
+
+// Create creates a new quote.
+func (db *Database) Create(q *quote.Quote) (*quote.Quote, error) {
+	// Map to local Quote type.
+	lq := &Quote{
+		ID:               q.ID,
+		Number:           q.Number,
+		FormID:           q.FormID,
+		FormModules:      FormJSON{Data: q.FormModules},
+		FormPricing:      FormJSON{Data: q.FormPricing},
+		Answers:          JSON{Data: q.Answers},
+		Estimate:         Estimate{Data: q.Estimate},
+		Status:           q.Status,
+		ExpiresAt:        q.ExpiresAt,
+		Created:          q.Created,
+		Updated:          q.Updated,
+		Token:            q.Token,
+		RequireSignature: q.RequireSignature,
+		Response:         Response{Data: q.Response},
+		FormRevision:     q.FormRevision,
+		FormVersion:      q.FormVersion,
+	}
+
+	// Execute the query.
+	if _, err := db.db.Exec(stmtInsert, lq.ID, lq.Number, lq.FormID, lq.FormModules, lq.FormPricing, lq.Answers, lq.Estimate, lq.Status, lq.ExpiresAt, lq.Created, lq.Updated, lq.Token, lq.RequireSignature, lq.Response, lq.FormRevision, lq.FormVersion); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// Get gets a page of quotes, newest first. An empty form ID or status
+// matches any.
+func (db *Database) Get(formID, status string, limit, offset int) ([]*quote.Quote, error) {
+	// Execute the query.
+	rows, err := db.db.Query(stmtGet, formID, formID, status, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Map rows to quotes.
+	quotes := []*quote.Quote{}
+	for rows.Next() {
+		q, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return quotes, nil
+}
+
+// GetByID gets a quote by the given ID.
+func (db *Database) GetByID(id string) (*quote.Quote, error) {
+	// Execute the query.
+	row := db.db.QueryRow(stmtGetByID, id)
+
+	// Map columns to quote.
+	q, err := scanQuote(row)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, quote.ErrQuoteNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// GetByToken gets a quote by the given public token.
+func (db *Database) GetByToken(token string) (*quote.Quote, error) {
+	// Execute the query.
+	row := db.db.QueryRow(stmtGetByToken, token)
+
+	// Map columns to quote.
+	q, err := scanQuote(row)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, quote.ErrQuoteNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// UpdateStatus updates the status of a quote from the given status to
+// another. The check and the update happen in a single statement, so
+// concurrent updates can't both succeed.
+func (db *Database) UpdateStatus(id, from, to string, updated time.Time) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtUpdateStatus, to, updated, id, from)
+	if err != nil {
+		return err
+	}
+
+	// Check the status was updated.
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if _, err := db.GetByID(id); err != nil {
+			return err
+		}
+		return quote.ErrStatusChanged
+	}
+
+	return nil
+}
+
+// Respond records a customer response to a quote, moving it from the given
+// status to the status of the response, unless the quote expired by the
+// updated time. The check and the update happen in a single statement, so a
+// quote can only be responded to once, and never once it has expired.
+func (db *Database) Respond(id, from string, r *quote.Response, updated time.Time) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtRespond, r.Status, Response{Data: r}, updated, id, from, updated)
+	if err != nil {
+		return err
+	}
+
+	// Check the response was recorded.
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if _, err := db.GetByID(id); err != nil {
+			return err
+		}
+		return quote.ErrStatusChanged
+	}
+
+	return nil
+}
+
+// GetExpired gets the open quotes whose expiry date is at or before the
+// given time.
+func (db *Database) GetExpired(now time.Time) ([]*quote.Quote, error) {
+	// Execute the query.
+	rows, err := db.db.Query(stmtGetExpired, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Map rows to quotes.
+	quotes := []*quote.Quote{}
+	for rows.Next() {
+		q, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return quotes, nil
+}
+
+// NextNumber counts a new quote for the given year, returning its number
+// within the year, starting at 1.
+func (db *Database) NextNumber(year int) (int, error) {
+	// Execute the query.
+	res, err := db.db.Exec(stmtNextNumber, year)
+	if err != nil {
+		return 0, err
+	}
+
+	// Get the count, which the statement sets as the last insert ID.
+	n, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
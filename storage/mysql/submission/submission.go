@@ -0,0 +1,156 @@
+package submission
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"estimator/storage/submission"
+)
+
+// Database defines the database.
+type Database struct {
+	db *sql.DB
+}
+
+// New creates a new database.
+func New(db *sql.DB) *Database {
+	return &Database{
+		db: db,
+	}
+}
+
+const (
+	// stmtInsert defines the SQL statement to
+	// insert a new submission into the database.
+	stmtInsert = `
+INSERT INTO submissions (id, form_id, answers, prefill, referrer, ip, coupon, created, form_revision)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+	// stmtGetByID defines the SQL statement to
+	// get a submission from the database.
+	stmtGetByID = `
+SELECT * FROM submissions
+WHERE id=?
+`
+)
+
+// Submission defines a submission.
+type Submission struct {
+	ID           string
+	FormID       string
+	Answers      Answers
+	Prefill      Prefill
+	Referrer     string
+	IP           string
+	Coupon       string
+	Created      time.Time
+	FormRevision int
+}
+
+// Answers defines submission answers.
+type Answers struct {
+	Data interface{}
+}
+
+// Value implements the driver interface.
+func (a Answers) Value() (driver.Value, error) {
+	b, err := json.Marshal(a.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (a *Answers) Scan(src any) error {
+	val := src.([]uint8)
+	return json.Unmarshal(val, &a.Data)
+}
+
+// Prefill defines submission prefill parameters.
+type Prefill struct {
+	Data map[string]string
+}
+
+// Value implements the driver interface.
+func (p Prefill) Value() (driver.Value, error) {
+	b, err := json.Marshal(p.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (p *Prefill) Scan(src any) error {
+	val := src.([]uint8)
+	return json.Unmarshal(val, &p.Data)
+}
+
+// Create creates a new submission.
+func (db *Database) Create(s *submission.Submission) (*submission.Submission, error) {
+	// Map to local Submission type.
+	ls := &Submission{
+		ID:     s.ID,
+		FormID: s.FormID,
+		Answers: Answers{
+			Data: s.Answers,
+		},
+		Prefill: Prefill{
+			Data: s.Prefill,
+		},
+		Referrer:     s.Referrer,
+		IP:           s.IP,
+		Coupon:       s.Coupon,
+		Created:      s.Created,
+		FormRevision: s.FormRevision,
+	}
+
+	// Execute the query.
+	if _, err := db.db.Exec(stmtInsert, ls.ID, ls.FormID, ls.Answers, ls.Prefill, ls.Referrer, ls.IP, ls.Coupon, ls.Created, ls.FormRevision); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// GetByID gets a submission by the given ID.
+func (db *Database) GetByID(id string) (*submission.Submission, error) {
+	// Create a new Submission.
+	s := &Submission{
+		Answers: Answers{},
+		Prefill: Prefill{},
+	}
+
+	// Execute the query.
+	row := db.db.QueryRow(stmtGetByID, id)
+
+	// Map columns to submission.
+	err := row.Scan(&s.ID, &s.FormID, &s.Answers, &s.Prefill, &s.Referrer, &s.IP, &s.Coupon, &s.Created, &s.FormRevision)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, submission.ErrSubmissionNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	// Map to storage submission type.
+	gs := &submission.Submission{
+		ID:           s.ID,
+		FormID:       s.FormID,
+		Answers:      s.Answers.Data,
+		Prefill:      s.Prefill.Data,
+		Referrer:     s.Referrer,
+		IP:           s.IP,
+		Coupon:       s.Coupon,
+		Created:      s.Created,
+		FormRevision: s.FormRevision,
+	}
+
+	return gs, nil
+}
@@ -0,0 +1,12 @@
+package quote
+
+import "errors"
+
+var (
+	// ErrQuoteNotFound is returned when a quote could not be found.
+	ErrQuoteNotFound = errors.New("quote could not be found")
+
+	// ErrStatusChanged is returned when the status of a quote was changed
+	// by another request before it could be updated.
+	ErrStatusChanged = errors.New("quote status has changed")
+)
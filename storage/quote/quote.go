@@ -0,0 +1,87 @@
+package quote
+
+import "time"
+
+// Database defines the quote database interface. Respond only records the
+// response while the quote has not passed its expiry date.
+type Database interface {
+	Create(q *Quote) (*Quote, error)
+	Get(formID, status string, limit, offset int) ([]*Quote, error)
+	GetByID(id string) (*Quote, error)
+	GetByToken(token string) (*Quote, error)
+	UpdateStatus(id, from, to string, updated time.Time) error
+	Respond(id, from string, r *Response, updated time.Time) error
+	GetExpired(now time.Time) ([]*Quote, error)
+	NextNumber(year int) (int, error)
+}
+
+// Quote defines a quote. The form modules, pricing settings and answers are
+// snapshotted as they were when the quote was created, from the form
+// revision and version given.
+type Quote struct {
+	ID               string
+	Number           string
+	Token            string
+	FormID           string
+	FormRevision     int
+	FormVersion      int
+	FormModules      interface{}
+	FormPricing      interface{}
+	Answers          interface{}
+	Estimate         *Estimate
+	Status           string
+	ExpiresAt        time.Time
+	RequireSignature bool
+	Response         *Response
+	Created          time.Time
+	Updated          time.Time
+}
+
+// Response defines a customer response to a quote.
+type Response struct {
+	Status    string      `json:"status"`
+	Name      string      `json:"name"`
+	Signature interface{} `json:"signature"`
+	IP        string      `json:"ip"`
+	UserAgent string      `json:"user_agent"`
+	At        time.Time   `json:"at"`
+}
+
+// Estimate defines the estimate of a quote. Amounts are whole numbers of
+// the minor unit of the currency, and decimals are exact decimal strings.
+type Estimate struct {
+	Lines      []Line      `json:"lines"`
+	Coupon     string      `json:"coupon"`
+	Currency   string      `json:"currency"`
+	Locale     string      `json:"locale"`
+	Subtotal   int64       `json:"subtotal"`
+	Tax        int64       `json:"tax"`
+	Total      int64       `json:"total"`
+	Low        int64       `json:"low"`
+	High       int64       `json:"high"`
+	Conversion *Conversion `json:"conversion"`
+}
+
+// Line defines an estimate line of a quote.
+type Line struct {
+	Kind        string  `json:"kind"`
+	Module      string  `json:"module"`
+	Description string  `json:"description"`
+	SKU         string  `json:"sku"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit"`
+	UnitPrice   string  `json:"unit_price"`
+	Amount      int64   `json:"amount"`
+	Low         int64   `json:"low"`
+	High        int64   `json:"high"`
+	Included    bool    `json:"included"`
+}
+
+// Conversion defines the exchange rate the estimate of a quote was
+// converted with.
+type Conversion struct {
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Rate    string    `json:"rate"`
+	Updated time.Time `json:"updated"`
+}
@@ -0,0 +1,241 @@
+package blob
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"estimator/storage/blob"
+)
+
+// emptyPayloadHash defines the SHA256 hash of an empty request body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// Config defines the S3-compatible store settings.
+type Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// Store defines the store.
+type Store struct {
+	cfg    Config
+	client *http.Client
+}
+
+// New creates a new store which keeps blobs in an S3-compatible bucket.
+// Objects are addressed path-style, so any S3-compatible server can be used
+// as the endpoint.
+func New(cfg Config) *Store {
+	return &Store{
+		cfg:    cfg,
+		client: &http.Client{},
+	}
+}
+
+// url returns the URL of the object with the given ID.
+func (s *Store) url(id string) string {
+	return strings.TrimRight(s.cfg.Endpoint, "/") + "/" + url.PathEscape(s.cfg.Bucket) + "/" + url.PathEscape(id)
+}
+
+// Put streams a new blob to the bucket.
+func (s *Store) Put(b *blob.Blob, r io.Reader) (*blob.Blob, error) {
+	// Spool the data to a temporary file, as the request must be signed
+	// with the payload hash and sent with a known length.
+	tmp, err := os.CreateTemp("", "blob-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	// Create the request.
+	req, err := http.NewRequest(http.MethodPut, s.url(b.ID), tmp)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", b.ContentType)
+	req.Header.Set("X-Amz-Meta-Filename", url.QueryEscape(b.Filename))
+	req.Header.Set("X-Amz-Meta-Created", b.Created.UTC().Format(time.RFC3339))
+	s.sign(req, hex.EncodeToString(h.Sum(nil)))
+
+	// Execute the request.
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("s3 put returned status %d", res.StatusCode)
+	}
+
+	b.Size = size
+
+	return b, nil
+}
+
+// Get gets a blob and its data by the given ID. The caller must close the
+// returned reader.
+func (s *Store) Get(id string) (*blob.Blob, io.ReadCloser, error) {
+	res, err := s.do(http.MethodGet, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return blobFromHeader(id, res.Header), res.Body, nil
+}
+
+// Stat gets a blob by the given ID without its data.
+func (s *Store) Stat(id string) (*blob.Blob, error) {
+	res, err := s.do(http.MethodHead, id)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+
+	return blobFromHeader(id, res.Header), nil
+}
+
+// Delete deletes a blob by the given ID.
+func (s *Store) Delete(id string) error {
+	res, err := s.do(http.MethodDelete, id)
+	switch {
+	case err == blob.ErrBlobNotFound:
+		return nil
+	case err != nil:
+		return err
+	}
+	res.Body.Close()
+
+	return nil
+}
+
+// do executes a signed request without a body against the object with the
+// given ID.
+func (s *Store) do(method, id string) (*http.Response, error) {
+	// Create the request.
+	req, err := http.NewRequest(method, s.url(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, emptyPayloadHash)
+
+	// Execute the request.
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		res.Body.Close()
+		return nil, blob.ErrBlobNotFound
+	case res.StatusCode < 200 || res.StatusCode > 299:
+		res.Body.Close()
+		return nil, fmt.Errorf("s3 %s returned status %d", strings.ToLower(method), res.StatusCode)
+	}
+
+	return res, nil
+}
+
+// sign signs the request using AWS Signature Version 4.
+func (s *Store) sign(req *http.Request, payloadHash string) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// Build the canonical headers.
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Build the canonical request and string to sign.
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	// Derive the signing key and sign.
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the data with the given key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// blobFromHeader maps object response headers to a storage blob.
+func blobFromHeader(id string, h http.Header) *blob.Blob {
+	b := &blob.Blob{
+		ID:          id,
+		ContentType: h.Get("Content-Type"),
+	}
+
+	// Handle filename.
+	if filename, err := url.QueryUnescape(h.Get("X-Amz-Meta-Filename")); err == nil {
+		b.Filename = filename
+	}
+
+	// Handle size.
+	fmt.Sscan(h.Get("Content-Length"), &b.Size)
+
+	// Handle created.
+	if created, err := time.Parse(time.RFC3339, h.Get("X-Amz-Meta-Created")); err == nil {
+		b.Created = created
+	}
+
+	return b
+}
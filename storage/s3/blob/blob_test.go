@@ -0,0 +1,254 @@
+package blob
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"estimator/storage/blob"
+)
+
+// object defines an object kept by the stand-in.
+type object struct {
+	header http.Header
+	data   []byte
+}
+
+// standIn is a minimal S3-compatible server which checks the request
+// signatures and keeps objects in memory.
+type standIn struct {
+	t         *testing.T
+	accessKey string
+	secretKey string
+	region    string
+
+	mu      sync.Mutex
+	objects map[string]object
+}
+
+func (s *standIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Check the signature.
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("reading body: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err := s.verify(r, body); err != "" {
+		s.t.Logf("rejected %s %s: %s", r.Method, r.URL.Path, err)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := r.URL.Path
+	switch r.Method {
+	case http.MethodPut:
+		h := http.Header{}
+		for k, v := range r.Header {
+			if k == "Content-Type" || strings.HasPrefix(k, "X-Amz-Meta-") {
+				h[k] = v
+			}
+		}
+		s.objects[key] = object{header: h, data: body}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		o, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for k, v := range o.header {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(o.data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(o.data)
+		}
+	case http.MethodDelete:
+		// S3 responds the same whether the object existed or not.
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verify recomputes the AWS Signature Version 4 of the request, returning
+// the reason it is invalid, if any.
+func (s *standIn) verify(r *http.Request, body []byte) string {
+	// Check the payload hash.
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	sum := sha256.Sum256(body)
+	if payloadHash != hex.EncodeToString(sum[:]) {
+		return "payload hash mismatch"
+	}
+
+	// Parse the authorization header.
+	auth := strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	parts := map[string]string{}
+	for _, v := range strings.Split(auth, ", ") {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			return "malformed authorization header"
+		}
+		parts[kv[0]] = kv[1]
+	}
+	credential := strings.Split(parts["Credential"], "/")
+	if len(credential) != 5 || credential[0] != s.accessKey || credential[2] != s.region || credential[3] != "s3" || credential[4] != "aws4_request" {
+		return "invalid credential"
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	if !strings.HasPrefix(amzDate, credential[1]) {
+		return "date does not match credential scope"
+	}
+
+	// Build the canonical request from the signed headers.
+	names := strings.Split(parts["SignedHeaders"], ";")
+	if !sort.StringsAreSorted(names) {
+		return "signed headers not sorted"
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		v := r.Header.Get(name)
+		if name == "host" {
+			v = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + v + "\n")
+	}
+	canonicalRequest := r.Method + "\n" + r.URL.EscapedPath() + "\n" + r.URL.RawQuery + "\n" + canonicalHeaders.String() + "\n" + parts["SignedHeaders"] + "\n" + payloadHash
+	crHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := strings.Join(credential[1:], "/")
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(crHash[:])
+
+	// Derive the signing key and compare.
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), credential[1])
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	if hex.EncodeToString(hmacSHA256(key, stringToSign)) != parts["Signature"] {
+		return "signature mismatch"
+	}
+
+	return ""
+}
+
+// newStandIn starts a stand-in and returns a store configured against it
+// with the given secret key.
+func newStandIn(t *testing.T, secretKey string) *Store {
+	si := &standIn{
+		t:         t,
+		accessKey: "estimator",
+		secretKey: "estimator-dev",
+		region:    "us-east-1",
+		objects:   map[string]object{},
+	}
+	srv := httptest.NewServer(si)
+	t.Cleanup(srv.Close)
+
+	return New(Config{
+		Endpoint:  srv.URL + "/",
+		Region:    si.region,
+		Bucket:    "estimator",
+		AccessKey: si.accessKey,
+		SecretKey: secretKey,
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	s := newStandIn(t, "estimator-dev")
+	data := bytes.Repeat([]byte("roof photo "), 10000)
+	created := time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+
+	// Put.
+	b, err := s.Put(&blob.Blob{
+		ID:          "0f8a/photo 1.jpg",
+		ContentType: "image/jpeg",
+		Filename:    "Roof photo ü.jpg",
+		Created:     created,
+	}, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if b.Size != int64(len(data)) {
+		t.Errorf("put size = %d, want %d", b.Size, len(data))
+	}
+
+	// Stat.
+	want := blob.Blob{
+		ID:          "0f8a/photo 1.jpg",
+		ContentType: "image/jpeg",
+		Filename:    "Roof photo ü.jpg",
+		Size:        int64(len(data)),
+		Created:     created,
+	}
+	sb, err := s.Stat(b.ID)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if *sb != want {
+		t.Errorf("stat = %+v, want %+v", *sb, want)
+	}
+
+	// Get.
+	gb, rc, err := s.Get(b.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("reading get body: %v", err)
+	}
+	if *gb != want {
+		t.Errorf("get = %+v, want %+v", *gb, want)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("get data differs from put data")
+	}
+
+	// Delete.
+	if err := s.Delete(b.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, _, err := s.Get(b.ID); err != blob.ErrBlobNotFound {
+		t.Errorf("get after delete err = %v, want ErrBlobNotFound", err)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	s := newStandIn(t, "estimator-dev")
+
+	if _, _, err := s.Get("missing"); err != blob.ErrBlobNotFound {
+		t.Errorf("get err = %v, want ErrBlobNotFound", err)
+	}
+	if _, err := s.Stat("missing"); err != blob.ErrBlobNotFound {
+		t.Errorf("stat err = %v, want ErrBlobNotFound", err)
+	}
+	if err := s.Delete("missing"); err != nil {
+		t.Errorf("delete err = %v, want nil", err)
+	}
+}
+
+func TestBadSignature(t *testing.T) {
+	s := newStandIn(t, "wrong-secret")
+
+	if _, err := s.Put(&blob.Blob{ID: "x", ContentType: "text/plain"}, strings.NewReader("x")); err == nil {
+		t.Error("put with wrong secret succeeded")
+	}
+	if _, err := s.Stat("x"); err == nil || err == blob.ErrBlobNotFound {
+		t.Errorf("stat with wrong secret err = %v, want status error", err)
+	}
+}
@@ -1,12 +1,26 @@
 package storage
 
 import (
+	"estimator/storage/blob"
+	"estimator/storage/catalog"
+	"estimator/storage/coupon"
+	"estimator/storage/exchangerate"
 	"estimator/storage/form"
+	"estimator/storage/formrevision"
+	"estimator/storage/quote"
+	"estimator/storage/submission"
 )
 
 // Storage defines the storage system.
 type Storage struct {
-	Form form.Database
+	Form         form.Database
+	FormRevision formrevision.Database
+	Submission   submission.Database
+	Blob         blob.Store
+	Catalog      catalog.Database
+	Coupon       coupon.Database
+	ExchangeRate exchangerate.Database
+	Quote        quote.Database
 }
 
 // New returns a new storage.
@@ -0,0 +1,8 @@
+package submission
+
+import "errors"
+
+var (
+	// ErrSubmissionNotFound is returned when a submission could not be found.
+	ErrSubmissionNotFound = errors.New("submission could not be found")
+)
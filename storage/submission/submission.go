@@ -0,0 +1,23 @@
+package submission
+
+import "time"
+
+// Database defines the submission database interface.
+type Database interface {
+	Create(s *Submission) (*Submission, error)
+	GetByID(id string) (*Submission, error)
+}
+
+// Submission defines a submission. FormRevision is the number of the form
+// revision it was made against.
+type Submission struct {
+	ID           string
+	FormID       string
+	Answers      interface{}
+	Prefill      map[string]string
+	Referrer     string
+	IP           string
+	Coupon       string
+	Created      time.Time
+	FormRevision int
+}
@@ -0,0 +1,32 @@
+package types
+
+import "regexp"
+
+// assetIDRegexp matches asset IDs, which are the hex SHA256 of the content.
+var assetIDRegexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Asset defines an uploaded image asset. Assets are content-addressed, so
+// the same image always has the same ID and URL.
+type Asset struct {
+	ID          string
+	URL         string
+	ContentType string
+	Size        int64
+	Width       int
+	Height      int
+}
+
+// ValidAssetID checks if the given string is a valid asset ID.
+func ValidAssetID(id string) bool {
+	return assetIDRegexp.MatchString(id)
+}
+
+// AssetURL returns the URL an asset is served from, or an empty string when
+// no asset is given.
+func AssetURL(id string) string {
+	if id == "" {
+		return ""
+	}
+
+	return "/api/v1/asset/" + id
+}
@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+// Blob defines a stored file.
+type Blob struct {
+	ID          string
+	ContentType string
+	Filename    string
+	Size        int64
+	Created     time.Time
+}
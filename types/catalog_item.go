@@ -0,0 +1,68 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CatalogItem defines a reusable product or service with a price, which
+// product list modules reference by ID. MinPrice and MaxPrice optionally
+// give the range of the unit price when it can't be known exactly.
+type CatalogItem struct {
+	ID          string
+	Name        string
+	SKU         string
+	Description string
+	UnitPrice   Decimal
+	MinPrice    Decimal
+	MaxPrice    Decimal
+	Unit        string
+	Image       string
+	MinQuantity int
+	MaxQuantity int
+}
+
+// Validate validates the catalog item. A max quantity of 0 means there is no
+// maximum.
+func (ci *CatalogItem) Validate() error {
+	// Check name and SKU.
+	if strings.TrimSpace(ci.Name) == "" {
+		return errors.New("name is required")
+	}
+	if strings.TrimSpace(ci.SKU) == "" {
+		return errors.New("sku is required")
+	}
+
+	// Check unit price.
+	if err := ValidatePriceRange(ci.UnitPrice, ci.MinPrice, ci.MaxPrice); err != nil {
+		return fmt.Errorf("invalid unit price, %v", err)
+	}
+
+	// Check quantities.
+	if ci.MinQuantity < 0 || ci.MaxQuantity < 0 {
+		return errors.New("min and max quantity must not be negative")
+	}
+	if ci.MaxQuantity > 0 && ci.MinQuantity > ci.MaxQuantity {
+		return errors.New("min quantity must not be greater than max quantity")
+	}
+
+	// Check image.
+	if ci.Image != "" && !ValidAssetID(ci.Image) {
+		return errors.New("image must be an asset ID")
+	}
+
+	return nil
+}
+
+// CheckQuantity checks the given quantity is within the item limits.
+func (ci *CatalogItem) CheckQuantity(q int) error {
+	if q < ci.MinQuantity {
+		return fmt.Errorf("must be at least %d", ci.MinQuantity)
+	}
+	if ci.MaxQuantity > 0 && q > ci.MaxQuantity {
+		return fmt.Errorf("must not be more than %d", ci.MaxQuantity)
+	}
+
+	return nil
+}
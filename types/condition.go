@@ -0,0 +1,159 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"estimator/utils"
+)
+
+// ConditionOperators defines the available operators for a condition.
+var ConditionOperators []string = []string{
+	"equals",
+	"not_equals",
+	"contains",
+	"greater_than",
+	"less_than",
+	"empty",
+	"not_empty",
+	"within_days",
+}
+
+// Condition defines a condition on the answer to another module, such as
+// "show this section when roof-type equals metal". The within_days operator
+// matches date and date range answers starting no more than Value days from
+// today, such as for a rush fee. Today is in the time zone of the date
+// module.
+type Condition struct {
+	Module   string      `json:"module"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// Locations maps the names of date and date range modules to their time
+// zones, which conditions on them are evaluated in.
+type Locations map[string]*time.Location
+
+// DateLocations gets the time zones of the date and date range modules of
+// the given modules, including nested ones.
+func DateLocations(modules []Module) Locations {
+	locations := make(Locations)
+	for _, module := range AllModules(modules) {
+		var name string
+		switch m := module.(type) {
+		case *Date:
+			name = m.Properties.TimeZone
+		case *DateRange:
+			name = m.Properties.TimeZone
+		default:
+			continue
+		}
+		if loc, err := LoadLocation(name); err == nil {
+			locations[module.GetName()] = loc
+		}
+	}
+
+	return locations
+}
+
+// Validate validates the condition.
+func (c *Condition) Validate() error {
+	if c.Module == "" {
+		return errors.New("invalid condition, module is required")
+	}
+	if !utils.SliceContains(ConditionOperators, c.Operator) {
+		return errors.New("invalid condition operator")
+	}
+	if c.Operator == "within_days" {
+		if days, ok := c.Value.(float64); !ok || days < 0 || days != float64(int(days)) {
+			return errors.New("invalid condition value, within_days must be a whole number of days")
+		}
+	}
+
+	return nil
+}
+
+// Evaluate evaluates the condition against the given answers, with today in
+// the time zone the locations give for the module, or in UTC.
+func (c *Condition) Evaluate(answers map[string]interface{}, locations Locations) bool {
+	answer := answers[c.Module]
+
+	switch c.Operator {
+	case "equals":
+		return reflect.DeepEqual(answer, c.Value)
+	case "not_equals":
+		return !reflect.DeepEqual(answer, c.Value)
+	case "contains":
+		switch a := answer.(type) {
+		case string:
+			v, ok := c.Value.(string)
+			return ok && strings.Contains(a, v)
+		case []interface{}:
+			for _, v := range a {
+				if reflect.DeepEqual(v, c.Value) {
+					return true
+				}
+			}
+		}
+		return false
+	case "greater_than":
+		a, aOK := answer.(float64)
+		v, vOK := c.Value.(float64)
+		return aOK && vOK && a > v
+	case "less_than":
+		a, aOK := answer.(float64)
+		v, vOK := c.Value.(float64)
+		return aOK && vOK && a < v
+	case "within_days":
+		loc := locations[c.Module]
+		if loc == nil {
+			loc = time.UTC
+		}
+		days, ok := c.Value.(float64)
+		return ok && withinDays(answer, int(days), time.Now().In(loc))
+	case "empty":
+		return isEmptyAnswer(answer)
+	case "not_empty":
+		return !isEmptyAnswer(answer)
+	}
+
+	return false
+}
+
+// withinDays checks if a date or date range answer starts between today and
+// the given number of days from today.
+func withinDays(answer interface{}, days int, now time.Time) bool {
+	// Get the date.
+	var s string
+	switch a := answer.(type) {
+	case string:
+		s = a
+	case map[string]interface{}:
+		s, _ = a["start"].(string)
+	}
+	date, err := time.ParseInLocation(DateLayout, s, now.Location())
+	if err != nil {
+		return false
+	}
+
+	today := Today(now)
+	return !date.Before(today) && !date.After(today.AddDate(0, 0, days))
+}
+
+// isEmptyAnswer checks if an answer is missing or has no value.
+func isEmptyAnswer(answer interface{}) bool {
+	switch a := answer.(type) {
+	case nil:
+		return true
+	case string:
+		return a == ""
+	case []interface{}:
+		return len(a) == 0
+	case map[string]interface{}:
+		return len(a) == 0
+	}
+
+	return false
+}
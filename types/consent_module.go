@@ -0,0 +1,106 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Consent defines the consent module.
+type Consent struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Properties ConsentProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (c *Consent) SetID(id string) {
+	c.ID = id
+}
+
+// GetID implements the Module interface.
+func (c *Consent) GetID() string {
+	return c.ID
+}
+
+// GetType implements the Module interface.
+func (c *Consent) GetType() string {
+	return c.Type
+}
+
+// GetName implements the Module interface.
+func (c *Consent) GetName() string {
+	return c.Name
+}
+
+// Validate implements the Module interface.
+func (c *Consent) Validate() error {
+	// Check type.
+	if err := ValidateType(c.Type); err != nil {
+		return err
+	}
+
+	// Check text and version.
+	if c.Properties.Text == "" && c.Properties.TermsURL == "" {
+		return errors.New("property text or terms url is required")
+	}
+	if c.Properties.Version == "" {
+		return errors.New("property version is required")
+	}
+
+	return nil
+}
+
+// ValidateAnswer implements the AnswerValidator interface. The answer is
+// expected to be an object with "accepted" and the "hash" of the text that
+// was shown, so consent to text that has since changed is rejected.
+func (c *Consent) ValidateAnswer(answer interface{}) error {
+	// Get the answer as a map.
+	var m map[string]interface{}
+	if answer != nil {
+		var ok bool
+		if m, ok = answer.(map[string]interface{}); !ok {
+			return fmt.Errorf("%s must be an object", c.Name)
+		}
+	}
+
+	// Handle not accepted.
+	accepted, _ := m["accepted"].(bool)
+	if !accepted {
+		if c.Properties.Required {
+			return fmt.Errorf("%s must be accepted", c.Name)
+		}
+		return nil
+	}
+
+	// Check the hash.
+	if hash, _ := m["hash"].(string); hash != c.Properties.Hash {
+		return fmt.Errorf("%s has changed, please review it again", c.Name)
+	}
+
+	return nil
+}
+
+// ConsentHash returns the SHA256 content hash of the consent text, terms URL
+// and version.
+func ConsentHash(p ConsentProperties) string {
+	h := sha256.New()
+	for _, v := range []string{p.Version, p.TermsURL, p.Text} {
+		fmt.Fprintf(h, "%d:%s", len(v), v)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ConsentProperties defines the consent module properties. Text is rich
+// text and Hash is always derived from the content with ConsentHash.
+type ConsentProperties struct {
+	Label    string `json:"label"`
+	Text     string `json:"text"`
+	TermsURL string `json:"terms_url"`
+	Version  string `json:"version"`
+	Required bool   `json:"required"`
+	Hash     string `json:"hash"`
+}
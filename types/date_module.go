@@ -0,0 +1,306 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"estimator/utils"
+)
+
+// DateLayout defines the layout dates are submitted and stored in.
+const DateLayout = "2006-01-02"
+
+// DateFormats defines the available display formats for a date module.
+var DateFormats []string = []string{
+	"YYYY-MM-DD",
+	"MM/DD/YYYY",
+	"DD/MM/YYYY",
+}
+
+// relativeDateRegexp matches relative date bounds such as "+2d" or "-1w".
+var relativeDateRegexp = regexp.MustCompile(`^([+-])(\d+)([dwmy])$`)
+
+// Date defines the date module.
+type Date struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Name       string         `json:"name"`
+	Properties DateProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (d *Date) SetID(id string) {
+	d.ID = id
+}
+
+// GetID implements the Module interface.
+func (d *Date) GetID() string {
+	return d.ID
+}
+
+// GetType implements the Module interface.
+func (d *Date) GetType() string {
+	return d.Type
+}
+
+// GetName implements the Module interface.
+func (d *Date) GetName() string {
+	return d.Name
+}
+
+// Validate implements the Module interface.
+func (d *Date) Validate() error {
+	// Check type.
+	if err := ValidateType(d.Type); err != nil {
+		return err
+	}
+
+	// Check format.
+	if !utils.SliceContains(DateFormats, d.Properties.Format) {
+		return errors.New("invalid property format")
+	}
+
+	// Check time zone.
+	loc, err := LoadLocation(d.Properties.TimeZone)
+	if err != nil {
+		return err
+	}
+	now := time.Now().In(loc)
+
+	// Check minimum and maximum dates.
+	min, err := ParseDateBound(d.Properties.MinDate, now)
+	if err != nil {
+		return errors.New("invalid property min date")
+	}
+	max, err := ParseDateBound(d.Properties.MaxDate, now)
+	if err != nil {
+		return errors.New("invalid property max date")
+	}
+	if !min.IsZero() && !max.IsZero() && min.After(max) {
+		return errors.New("property min date must not be after max date")
+	}
+
+	// Check disabled weekdays and blackout dates.
+	if err := validateWeekdays(d.Properties.DisabledWeekdays); err != nil {
+		return err
+	}
+	if err := validateBlackoutDates(d.Properties.BlackoutDates); err != nil {
+		return err
+	}
+
+	// Check default.
+	if d.Properties.Default != "" {
+		if _, err := time.Parse(DateLayout, d.Properties.Default); err != nil {
+			return errors.New("invalid property default, must be in the format YYYY-MM-DD")
+		}
+	}
+
+	// Check rush fee.
+	if d.Properties.RushDays < 0 {
+		return errors.New("property rush days must not be negative")
+	}
+	if d.Properties.RushFee < 0 {
+		return errors.New("property rush fee must not be negative")
+	}
+
+	return nil
+}
+
+// ValidateAnswer implements the AnswerValidator interface.
+func (d *Date) ValidateAnswer(answer interface{}) error {
+	// Handle empty answers.
+	if answer == nil || answer == "" {
+		if d.Properties.Required {
+			return fmt.Errorf("%s is required", d.Name)
+		}
+		return nil
+	}
+
+	// Get the answer as a string.
+	s, ok := answer.(string)
+	if !ok {
+		return fmt.Errorf("%s must be a date string", d.Name)
+	}
+
+	// Parse the date in the module time zone.
+	loc, err := LoadLocation(d.Properties.TimeZone)
+	if err != nil {
+		return err
+	}
+	date, err := time.ParseInLocation(DateLayout, s, loc)
+	if err != nil {
+		return fmt.Errorf("%s must be in the format YYYY-MM-DD", d.Name)
+	}
+
+	return checkDate(d.Name, date, d.Properties.MinDate, d.Properties.MaxDate, d.Properties.DisabledWeekdays, d.Properties.BlackoutDates)
+}
+
+// Prefill implements the Prefiller interface, overriding the default with
+// the prefill parameter when one is set and holds a selectable date.
+func (d *Date) Prefill(params url.Values, referrer string) []string {
+	if d.Properties.PrefillParam == "" || !params.Has(d.Properties.PrefillParam) {
+		return nil
+	}
+
+	v := params.Get(d.Properties.PrefillParam)
+	if v == "" || d.ValidateAnswer(v) != nil {
+		return nil
+	}
+	d.Properties.Default = v
+
+	return []string{d.Properties.PrefillParam}
+}
+
+// RushFee returns the rush fee for the given answer, which is zero unless
+// the date starts within the rush days of today in the module time zone.
+func (d *Date) RushFee(answer interface{}) Decimal {
+	loc, err := LoadLocation(d.Properties.TimeZone)
+	if err != nil || !withinDays(answer, d.Properties.RushDays, time.Now().In(loc)) {
+		return 0
+	}
+
+	return d.Properties.RushFee
+}
+
+// DateProperties defines the date module properties. The rush fee is
+// charged when the date starts no more than RushDays days from today.
+type DateProperties struct {
+	Label            string   `json:"label"`
+	Sublabel         string   `json:"sublabel"`
+	Tooltip          string   `json:"tooltip"`
+	Required         bool     `json:"required"`
+	MinDate          string   `json:"min_date"`
+	MaxDate          string   `json:"max_date"`
+	DisabledWeekdays []int    `json:"disabled_weekdays"`
+	BlackoutDates    []string `json:"blackout_dates"`
+	Format           string   `json:"format"`
+	TimeZone         string   `json:"time_zone"`
+	RushDays         int      `json:"rush_days"`
+	RushFee          Decimal  `json:"rush_fee"`
+	Default          string   `json:"default"`
+	PrefillParam     string   `json:"prefill_param"`
+}
+
+// LoadLocation loads the time zone with the given IANA name, defaulting to
+// UTC when no name is given.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("invalid property time zone")
+	}
+
+	return loc, nil
+}
+
+// Today returns midnight of the given time's day in its location.
+func Today(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// ParseDateBound parses a date bound which is either an absolute date in the
+// format YYYY-MM-DD, "today", or a date relative to today such as "+2d",
+// "-1w", "+3m" or "+1y". An empty bound returns the zero time.
+func ParseDateBound(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	// Handle today.
+	today := Today(now)
+	if s == "today" {
+		return today, nil
+	}
+
+	// Handle relative dates.
+	if m := relativeDateRegexp.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[2])
+		if err != nil {
+			return time.Time{}, err
+		}
+		if m[1] == "-" {
+			n = -n
+		}
+
+		switch m[3] {
+		case "d":
+			return today.AddDate(0, 0, n), nil
+		case "w":
+			return today.AddDate(0, 0, n*7), nil
+		case "m":
+			return today.AddDate(0, n, 0), nil
+		default:
+			return today.AddDate(n, 0, 0), nil
+		}
+	}
+
+	// Handle absolute dates.
+	return time.ParseInLocation(DateLayout, s, now.Location())
+}
+
+// validateWeekdays validates a list of disabled weekdays, where Sunday is 0
+// and Saturday is 6.
+func validateWeekdays(weekdays []int) error {
+	for _, v := range weekdays {
+		if v < 0 || v > 6 {
+			return errors.New("invalid property disabled weekdays, must be between 0 and 6")
+		}
+	}
+
+	return nil
+}
+
+// validateBlackoutDates validates a list of blackout dates.
+func validateBlackoutDates(dates []string) error {
+	for _, v := range dates {
+		if _, err := time.Parse(DateLayout, v); err != nil {
+			return errors.New("invalid property blackout dates, must be in the format YYYY-MM-DD")
+		}
+	}
+
+	return nil
+}
+
+// checkDate checks that the given date is selectable under the given
+// constraints. Relative bounds are resolved against the current day in the
+// date's location.
+func checkDate(name string, date time.Time, minDate, maxDate string, disabledWeekdays []int, blackoutDates []string) error {
+	now := time.Now().In(date.Location())
+
+	// Check minimum date.
+	min, err := ParseDateBound(minDate, now)
+	if err != nil {
+		return errors.New("invalid property min date")
+	}
+	if !min.IsZero() && date.Before(min) {
+		return fmt.Errorf("%s must not be before %s", name, min.Format(DateLayout))
+	}
+
+	// Check maximum date.
+	max, err := ParseDateBound(maxDate, now)
+	if err != nil {
+		return errors.New("invalid property max date")
+	}
+	if !max.IsZero() && date.After(max) {
+		return fmt.Errorf("%s must not be after %s", name, max.Format(DateLayout))
+	}
+
+	// Check disabled weekdays.
+	if utils.SliceContains(disabledWeekdays, int(date.Weekday())) {
+		return fmt.Errorf("%s falls on an unavailable weekday", name)
+	}
+
+	// Check blackout dates.
+	if utils.SliceContains(blackoutDates, date.Format(DateLayout)) {
+		return fmt.Errorf("%s falls on an unavailable date", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"estimator/utils"
+)
+
+// DateRange defines the date range module.
+type DateRange struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	Name       string              `json:"name"`
+	Properties DateRangeProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (dr *DateRange) SetID(id string) {
+	dr.ID = id
+}
+
+// GetID implements the Module interface.
+func (dr *DateRange) GetID() string {
+	return dr.ID
+}
+
+// GetType implements the Module interface.
+func (dr *DateRange) GetType() string {
+	return dr.Type
+}
+
+// GetName implements the Module interface.
+func (dr *DateRange) GetName() string {
+	return dr.Name
+}
+
+// Validate implements the Module interface.
+func (dr *DateRange) Validate() error {
+	// Check type.
+	if err := ValidateType(dr.Type); err != nil {
+		return err
+	}
+
+	// Check format.
+	if !utils.SliceContains(DateFormats, dr.Properties.Format) {
+		return errors.New("invalid property format")
+	}
+
+	// Check time zone.
+	loc, err := LoadLocation(dr.Properties.TimeZone)
+	if err != nil {
+		return err
+	}
+	now := time.Now().In(loc)
+
+	// Check minimum and maximum dates.
+	min, err := ParseDateBound(dr.Properties.MinDate, now)
+	if err != nil {
+		return errors.New("invalid property min date")
+	}
+	max, err := ParseDateBound(dr.Properties.MaxDate, now)
+	if err != nil {
+		return errors.New("invalid property max date")
+	}
+	if !min.IsZero() && !max.IsZero() && min.After(max) {
+		return errors.New("property min date must not be after max date")
+	}
+
+	// Check minimum and maximum days.
+	if dr.Properties.MinDays < 0 || dr.Properties.MaxDays < 0 {
+		return errors.New("invalid property min or max days, must not be negative")
+	}
+	if dr.Properties.MaxDays > 0 && dr.Properties.MinDays > dr.Properties.MaxDays {
+		return errors.New("property min days must not be greater than max days")
+	}
+
+	// Check disabled weekdays and blackout dates.
+	if err := validateWeekdays(dr.Properties.DisabledWeekdays); err != nil {
+		return err
+	}
+	if err := validateBlackoutDates(dr.Properties.BlackoutDates); err != nil {
+		return err
+	}
+
+	// Check rush fee.
+	if dr.Properties.RushDays < 0 {
+		return errors.New("property rush days must not be negative")
+	}
+	if dr.Properties.RushFee < 0 {
+		return errors.New("property rush fee must not be negative")
+	}
+
+	return nil
+}
+
+// ValidateAnswer implements the AnswerValidator interface. The answer is
+// expected to be an object with "start" and "end" dates.
+func (dr *DateRange) ValidateAnswer(answer interface{}) error {
+	// Handle empty answers.
+	if answer == nil {
+		if dr.Properties.Required {
+			return fmt.Errorf("%s is required", dr.Name)
+		}
+		return nil
+	}
+
+	// Get the answer as a map.
+	m, ok := answer.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s must be an object with start and end dates", dr.Name)
+	}
+
+	// Parse the start and end dates in the module time zone.
+	loc, err := LoadLocation(dr.Properties.TimeZone)
+	if err != nil {
+		return err
+	}
+	startStr, ok := m["start"].(string)
+	if !ok {
+		return fmt.Errorf("%s start must be a date string", dr.Name)
+	}
+	start, err := time.ParseInLocation(DateLayout, startStr, loc)
+	if err != nil {
+		return fmt.Errorf("%s start must be in the format YYYY-MM-DD", dr.Name)
+	}
+	endStr, ok := m["end"].(string)
+	if !ok {
+		return fmt.Errorf("%s end must be a date string", dr.Name)
+	}
+	end, err := time.ParseInLocation(DateLayout, endStr, loc)
+	if err != nil {
+		return fmt.Errorf("%s end must be in the format YYYY-MM-DD", dr.Name)
+	}
+
+	// Check the start and end dates individually.
+	if err := checkDate(dr.Name+" start", start, dr.Properties.MinDate, dr.Properties.MaxDate, dr.Properties.DisabledWeekdays, dr.Properties.BlackoutDates); err != nil {
+		return err
+	}
+	if err := checkDate(dr.Name+" end", end, dr.Properties.MinDate, dr.Properties.MaxDate, dr.Properties.DisabledWeekdays, dr.Properties.BlackoutDates); err != nil {
+		return err
+	}
+
+	// Check the range order and length, counting both ends.
+	if end.Before(start) {
+		return fmt.Errorf("%s end must not be before start", dr.Name)
+	}
+	days := int(end.Sub(start).Hours()/24+0.5) + 1
+	if dr.Properties.MinDays > 0 && days < dr.Properties.MinDays {
+		return fmt.Errorf("%s must span at least %d days", dr.Name, dr.Properties.MinDays)
+	}
+	if dr.Properties.MaxDays > 0 && days > dr.Properties.MaxDays {
+		return fmt.Errorf("%s must not span more than %d days", dr.Name, dr.Properties.MaxDays)
+	}
+
+	// Check no blackout date falls within the range.
+	for _, v := range dr.Properties.BlackoutDates {
+		if v > startStr && v < endStr {
+			return fmt.Errorf("%s includes the unavailable date %s", dr.Name, v)
+		}
+	}
+
+	return nil
+}
+
+// RushFee returns the rush fee for the given answer, which is zero unless
+// the date range starts within the rush days of today in the module time zone.
+func (dr *DateRange) RushFee(answer interface{}) Decimal {
+	loc, err := LoadLocation(dr.Properties.TimeZone)
+	if err != nil || !withinDays(answer, dr.Properties.RushDays, time.Now().In(loc)) {
+		return 0
+	}
+
+	return dr.Properties.RushFee
+}
+
+// DateRangeProperties defines the date range module properties. The rush
+// fee is charged when the date range starts no more than RushDays days from
+// today.
+type DateRangeProperties struct {
+	Label            string   `json:"label"`
+	Sublabel         string   `json:"sublabel"`
+	Tooltip          string   `json:"tooltip"`
+	Required         bool     `json:"required"`
+	MinDate          string   `json:"min_date"`
+	MaxDate          string   `json:"max_date"`
+	MinDays          int      `json:"min_days"`
+	MaxDays          int      `json:"max_days"`
+	DisabledWeekdays []int    `json:"disabled_weekdays"`
+	BlackoutDates    []string `json:"blackout_dates"`
+	Format           string   `json:"format"`
+	TimeZone         string   `json:"time_zone"`
+	RushDays         int      `json:"rush_days"`
+	RushFee          Decimal  `json:"rush_fee"`
+}
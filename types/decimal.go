@@ -0,0 +1,163 @@
+package types
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DecimalPlaces defines the number of decimal places a decimal holds.
+const DecimalPlaces = 6
+
+// decimalScale defines the number of decimal units in one.
+const decimalScale = 1000000
+
+// ErrOutOfRange is returned when the result of decimal or money arithmetic
+// does not fit in its range.
+var ErrOutOfRange = errors.New("amount is out of range")
+
+// Decimal defines an exact decimal number with up to six decimal places,
+// such as a unit price or a tax rate, stored as an integer number of
+// millionths. In JSON it is a number, or a string holding a number.
+type Decimal int64
+
+// ParseDecimal parses a decimal number such as "12.34" or "-0.035".
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+
+	// Get the sign.
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	// Split the whole and fractional parts.
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, errors.New("invalid decimal")
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > DecimalPlaces {
+		return 0, errors.New("decimal has too many decimal places")
+	}
+	for _, r := range whole + frac {
+		if r < '0' || r > '9' {
+			return 0, errors.New("invalid decimal")
+		}
+	}
+
+	// Parse the digits as a whole number of millionths.
+	digits := strings.TrimLeft(whole+frac+strings.Repeat("0", DecimalPlaces-len(frac)), "0")
+	if digits == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, errors.New("decimal is out of range")
+	}
+	if neg {
+		v = -v
+	}
+
+	return Decimal(v), nil
+}
+
+// DecimalFromFloat converts a float, such as a number decoded from JSON, to
+// a decimal. The shortest decimal representation of the float is used, so
+// 0.1 converts to exactly 0.1, and more than six decimal places are rounded
+// half to even.
+func DecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errors.New("invalid decimal")
+	}
+
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return 0, errors.New("invalid decimal")
+	}
+	r.Mul(r, big.NewRat(decimalScale, 1))
+	v := roundRat(r.Num(), r.Denom(), RoundHalfEven)
+	if !v.IsInt64() {
+		return 0, errors.New("decimal is out of range")
+	}
+
+	return Decimal(v.Int64()), nil
+}
+
+// DecimalFromInt converts a whole number to a decimal.
+func DecimalFromInt(i int64) Decimal {
+	return Decimal(i * decimalScale)
+}
+
+// Mul returns the product of the decimals, rounded half to even.
+func (d Decimal) Mul(o Decimal) (Decimal, error) {
+	num := new(big.Int).Mul(big.NewInt(int64(d)), big.NewInt(int64(o)))
+	v, err := toInt64(roundRat(num, big.NewInt(decimalScale), RoundHalfEven))
+	return Decimal(v), err
+}
+
+// Inverse returns one divided by the decimal, rounded half to even, such
+// as the inverse of an exchange rate.
+func (d Decimal) Inverse() (Decimal, error) {
+	if d == 0 {
+		return 0, errors.New("can't invert zero")
+	}
+	num := new(big.Int).Mul(big.NewInt(decimalScale), big.NewInt(decimalScale))
+	v, err := toInt64(roundRat(num, big.NewInt(int64(d)), RoundHalfEven))
+	return Decimal(v), err
+}
+
+// String returns the decimal formatted without trailing zeros, such as
+// "12.34".
+func (d Decimal) String() string {
+	v := int64(d)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	s := strconv.FormatInt(v/decimalScale, 10)
+	if frac := v % decimalScale; frac != 0 {
+		s += "." + strings.TrimRight(strconv.FormatInt(frac+decimalScale, 10)[1:], "0")
+	}
+
+	return sign + s
+}
+
+// Float64 returns the decimal as a float.
+func (d Decimal) Float64() float64 {
+	return float64(d) / decimalScale
+}
+
+// toInt64 returns the whole number as an int64, or ErrOutOfRange when it
+// does not fit.
+func toInt64(v *big.Int) (int64, error) {
+	if !v.IsInt64() {
+		return 0, ErrOutOfRange
+	}
+
+	return v.Int64(), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	v, err := ParseDecimal(strings.Trim(string(b), `"`))
+	if err != nil {
+		return err
+	}
+	*d = v
+
+	return nil
+}
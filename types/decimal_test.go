@@ -0,0 +1,262 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+// dec parses the given decimal, failing the test if it is invalid.
+func dec(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", s, err)
+	}
+	return d
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Decimal
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "12.34", want: 12340000},
+		{in: "-0.035", want: -35000},
+		{in: "+1", want: 1000000},
+		{in: ".5", want: 500000},
+		{in: "5.", want: 5000000},
+		{in: " 7.100000 ", want: 7100000},
+		{in: "0.000001", want: 1},
+		{in: "9223372036854.775807", want: math.MaxInt64},
+		{in: "0.0000001", wantErr: true},
+		{in: "9223372036854.775808", wantErr: true},
+		{in: "", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "1e3", wantErr: true},
+		{in: "1,5", wantErr: true},
+		{in: "--1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.in)
+		switch {
+		case tt.wantErr && err == nil:
+			t.Errorf("ParseDecimal(%q) = %v, want an error", tt.in, got)
+		case !tt.wantErr && err != nil:
+			t.Errorf("ParseDecimal(%q) error: %v", tt.in, err)
+		case got != tt.want:
+			t.Errorf("ParseDecimal(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalFromFloat(t *testing.T) {
+	tests := []struct {
+		in      float64
+		want    Decimal
+		wantErr bool
+	}{
+		{in: 0.1, want: 100000},
+		{in: 19.99, want: 19990000},
+		{in: -2.5, want: -2500000},
+		{in: 0.0000005, want: 0},
+		{in: 0.0000015, want: 2},
+		{in: 1e12, want: 1000000000000000000},
+		{in: 1e13, wantErr: true},
+		{in: math.NaN(), wantErr: true},
+		{in: math.Inf(1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := DecimalFromFloat(tt.in)
+		switch {
+		case tt.wantErr && err == nil:
+			t.Errorf("DecimalFromFloat(%v) = %v, want an error", tt.in, got)
+		case !tt.wantErr && err != nil:
+			t.Errorf("DecimalFromFloat(%v) error: %v", tt.in, err)
+		case !tt.wantErr && got != tt.want:
+			t.Errorf("DecimalFromFloat(%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalMul(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    string
+		wantErr bool
+	}{
+		{a: "1.5", b: "2", want: "3"},
+		{a: "0.1", b: "0.1", want: "0.01"},
+		{a: "0.000001", b: "0.5", want: "0"},
+		{a: "0.000003", b: "0.5", want: "0.000002"},
+		{a: "-0.000003", b: "0.5", want: "-0.000002"},
+		{a: "1000000", b: "1000000", want: "1000000000000"},
+		{a: "10000000", b: "10000000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := dec(t, tt.a).Mul(dec(t, tt.b))
+		switch {
+		case tt.wantErr && err != ErrOutOfRange:
+			t.Errorf("%s * %s error = %v, want ErrOutOfRange", tt.a, tt.b, err)
+		case !tt.wantErr && err != nil:
+			t.Errorf("%s * %s error: %v", tt.a, tt.b, err)
+		case !tt.wantErr && got.String() != tt.want:
+			t.Errorf("%s * %s = %s, want %s", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalInverse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "2", want: "0.5"},
+		{in: "3", want: "0.333333"},
+		{in: "1.5", want: "0.666667"},
+		{in: "-4", want: "-0.25"},
+		{in: "0.000001", want: "1000000"},
+		{in: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := dec(t, tt.in).Inverse()
+		switch {
+		case tt.wantErr && err == nil:
+			t.Errorf("1 / %s = %s, want an error", tt.in, got)
+		case !tt.wantErr && err != nil:
+			t.Errorf("1 / %s error: %v", tt.in, err)
+		case !tt.wantErr && got.String() != tt.want:
+			t.Errorf("1 / %s = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyFromProduct(t *testing.T) {
+	tests := []struct {
+		price, quantity string
+		currency, mode  string
+		want            int64
+		wantErr         bool
+	}{
+		{price: "0.125", quantity: "1", currency: "USD", mode: RoundHalfUp, want: 13},
+		{price: "0.125", quantity: "1", currency: "USD", mode: RoundHalfEven, want: 12},
+		{price: "0.135", quantity: "1", currency: "USD", mode: RoundHalfEven, want: 14},
+		{price: "0.129", quantity: "1", currency: "USD", mode: RoundDown, want: 12},
+		{price: "0.121", quantity: "1", currency: "USD", mode: RoundUp, want: 13},
+		{price: "-0.125", quantity: "1", currency: "USD", mode: RoundHalfUp, want: -13},
+		{price: "-0.121", quantity: "1", currency: "USD", mode: RoundUp, want: -13},
+		{price: "-0.129", quantity: "1", currency: "USD", mode: RoundDown, want: -12},
+		{price: "12.345", quantity: "3", currency: "USD", mode: RoundHalfUp, want: 3704},
+		{price: "0.333333", quantity: "3", currency: "USD", mode: RoundHalfUp, want: 100},
+		{price: "1250.5", quantity: "1", currency: "JPY", mode: RoundHalfEven, want: 1250},
+		{price: "1.2345", quantity: "1", currency: "KWD", mode: RoundHalfUp, want: 1235},
+		{price: "9000000000000", quantity: "9000000", currency: "USD", mode: RoundHalfUp, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := MoneyFromProduct(dec(t, tt.price), dec(t, tt.quantity), tt.currency, tt.mode)
+		switch {
+		case tt.wantErr && err != ErrOutOfRange:
+			t.Errorf("%s x %s %s %s error = %v, want ErrOutOfRange", tt.price, tt.quantity, tt.currency, tt.mode, err)
+		case !tt.wantErr && err != nil:
+			t.Errorf("%s x %s %s %s error: %v", tt.price, tt.quantity, tt.currency, tt.mode, err)
+		case !tt.wantErr && (got.Amount != tt.want || got.Currency != tt.currency):
+			t.Errorf("%s x %s %s %s = %d %s, want %d", tt.price, tt.quantity, tt.currency, tt.mode, got.Amount, got.Currency, tt.want)
+		}
+	}
+}
+
+func TestMoneyMulFrac(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		num, den string
+		mode     string
+		want     int64
+		wantErr  bool
+	}{
+		{amount: 1005, num: "10", den: "100", mode: RoundHalfUp, want: 101},
+		{amount: 1005, num: "10", den: "100", mode: RoundHalfEven, want: 100},
+		{amount: 1015, num: "10", den: "100", mode: RoundHalfEven, want: 102},
+		{amount: 999, num: "10", den: "100", mode: RoundDown, want: 99},
+		{amount: 991, num: "10", den: "100", mode: RoundUp, want: 100},
+		{amount: 10800, num: "8", den: "108", mode: RoundHalfUp, want: 800},
+		{amount: 1000, num: "1", den: "0", mode: RoundHalfUp, wantErr: true},
+		{amount: math.MaxInt64, num: "2", den: "1", mode: RoundHalfUp, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		m := Money{Amount: tt.amount, Currency: "USD"}
+		got, err := m.MulFrac(dec(t, tt.num), dec(t, tt.den), tt.mode)
+		switch {
+		case tt.wantErr && err == nil:
+			t.Errorf("%d * %s / %s = %d, want an error", tt.amount, tt.num, tt.den, got.Amount)
+		case !tt.wantErr && err != nil:
+			t.Errorf("%d * %s / %s error: %v", tt.amount, tt.num, tt.den, err)
+		case !tt.wantErr && got.Amount != tt.want:
+			t.Errorf("%d * %s / %s %s = %d, want %d", tt.amount, tt.num, tt.den, tt.mode, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		from, to string
+		rate     string
+		mode     string
+		want     int64
+		wantErr  bool
+	}{
+		{amount: 1000, from: "USD", to: "EUR", rate: "0.9215", mode: RoundHalfUp, want: 922},
+		{amount: 1000, from: "USD", to: "EUR", rate: "0.9215", mode: RoundDown, want: 921},
+		{amount: 1000, from: "USD", to: "EUR", rate: "0.9225", mode: RoundHalfEven, want: 922},
+		{amount: 1000, from: "USD", to: "JPY", rate: "149.55", mode: RoundHalfUp, want: 1496},
+		{amount: 1000, from: "USD", to: "JPY", rate: "149.55", mode: RoundHalfEven, want: 1496},
+		{amount: 1500, from: "JPY", to: "USD", rate: "0.006687", mode: RoundUp, want: 1004},
+		{amount: 1000, from: "USD", to: "KWD", rate: "0.3075", mode: RoundHalfUp, want: 3075},
+		{amount: math.MaxInt64 / 2, from: "USD", to: "JPY", rate: "1500", mode: RoundHalfUp, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		m := Money{Amount: tt.amount, Currency: tt.from}
+		got, err := m.Convert(dec(t, tt.rate), tt.to, tt.mode)
+		switch {
+		case tt.wantErr && err != ErrOutOfRange:
+			t.Errorf("converting %d %s to %s error = %v, want ErrOutOfRange", tt.amount, tt.from, tt.to, err)
+		case !tt.wantErr && err != nil:
+			t.Errorf("converting %d %s to %s error: %v", tt.amount, tt.from, tt.to, err)
+		case !tt.wantErr && (got.Amount != tt.want || got.Currency != tt.to):
+			t.Errorf("converting %d %s to %s %s = %d %s, want %d", tt.amount, tt.from, tt.to, tt.mode, got.Amount, got.Currency, tt.want)
+		}
+	}
+}
+
+func TestMoneyArithmeticOverflow(t *testing.T) {
+	max := Money{Amount: math.MaxInt64, Currency: "USD"}
+	min := Money{Amount: math.MinInt64, Currency: "USD"}
+	one := Money{Amount: 1, Currency: "USD"}
+
+	if _, err := max.Add(one); err != ErrOutOfRange {
+		t.Errorf("max + 1 error = %v, want ErrOutOfRange", err)
+	}
+	if _, err := min.Sub(one); err != ErrOutOfRange {
+		t.Errorf("min - 1 error = %v, want ErrOutOfRange", err)
+	}
+	if _, err := min.Neg(); err != ErrOutOfRange {
+		t.Errorf("-min error = %v, want ErrOutOfRange", err)
+	}
+	if _, err := max.Decimal(); err != ErrOutOfRange {
+		t.Errorf("max as a decimal error = %v, want ErrOutOfRange", err)
+	}
+
+	got, err := max.Sub(one)
+	if err != nil || got.Amount != math.MaxInt64-1 {
+		t.Errorf("max - 1 = %d, %v", got.Amount, err)
+	}
+}
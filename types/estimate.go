@@ -0,0 +1,59 @@
+package types
+
+// Estimate line kinds.
+const (
+	LineItem     = "item"
+	LineFee      = "fee"
+	LineDiscount = "discount"
+	LineCoupon   = "coupon"
+	LineMinimum  = "minimum"
+	LineTax      = "tax"
+)
+
+// Estimate defines a computed estimate for a set of form answers. Subtotal
+// is the total of the item lines, and Tax the total of the tax lines,
+// including inclusive taxes. Subtotal, Tax and Total are the expected
+// amounts, and Low and High the range of the total. All amounts are in
+// Currency, and Locale is the locale to format them for. Conversion is set
+// when the estimate was converted from the form currency.
+type Estimate struct {
+	FormID     string
+	Lines      []EstimateLine
+	Coupon     string
+	Currency   string
+	Locale     string
+	Subtotal   Money
+	Tax        Money
+	Total      Money
+	Low        Money
+	High       Money
+	Conversion *Conversion
+}
+
+// EstimateLine defines a line of an estimate. Module is the name of the
+// module the line was produced by, if any. Included lines, such as
+// inclusive taxes, are shown but not added to the total. Amount is the
+// expected amount, and Low and High its range.
+type EstimateLine struct {
+	Kind        string
+	Module      string
+	Description string
+	SKU         string
+	Quantity    float64
+	Unit        string
+	UnitPrice   Decimal
+	Amount      Money
+	Low         Money
+	High        Money
+	Included    bool
+}
+
+// Range returns the amount of the line as a price range.
+func (el *EstimateLine) Range() PriceRange {
+	return PriceRange{Low: el.Low, Expected: el.Amount, High: el.High}
+}
+
+// SetRange sets the amount of the line from a price range.
+func (el *EstimateLine) SetRange(pr PriceRange) {
+	el.Low, el.Amount, el.High = pr.Low, pr.Expected, pr.High
+}
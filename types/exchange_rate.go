@@ -0,0 +1,38 @@
+package types
+
+import (
+	"errors"
+	"time"
+)
+
+// ExchangeRate defines the rate to convert an amount in the Base currency
+// to the Quote currency, such as 1.36 for USD to CAD.
+type ExchangeRate struct {
+	Base    string    `json:"base"`
+	Quote   string    `json:"quote"`
+	Rate    Decimal   `json:"rate"`
+	Updated time.Time `json:"updated"`
+}
+
+// Validate validates the exchange rate.
+func (er *ExchangeRate) Validate() error {
+	if !ValidCurrency(er.Base) || !ValidCurrency(er.Quote) {
+		return errors.New("invalid currency, must be a supported ISO 4217 code")
+	}
+	if er.Base == er.Quote {
+		return errors.New("base and quote currencies must be different")
+	}
+	if er.Rate <= 0 {
+		return errors.New("rate must be greater than zero")
+	}
+
+	return nil
+}
+
+// Conversion records the exchange rate an estimate was converted with.
+type Conversion struct {
+	From    string
+	To      string
+	Rate    Decimal
+	Updated time.Time
+}
@@ -0,0 +1,136 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// FileUpload defines the file upload module.
+type FileUpload struct {
+	ID         string               `json:"id"`
+	Type       string               `json:"type"`
+	Name       string               `json:"name"`
+	Properties FileUploadProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (fu *FileUpload) SetID(id string) {
+	fu.ID = id
+}
+
+// GetID implements the Module interface.
+func (fu *FileUpload) GetID() string {
+	return fu.ID
+}
+
+// GetType implements the Module interface.
+func (fu *FileUpload) GetType() string {
+	return fu.Type
+}
+
+// GetName implements the Module interface.
+func (fu *FileUpload) GetName() string {
+	return fu.Name
+}
+
+// Validate implements the Module interface.
+func (fu *FileUpload) Validate() error {
+	// Check type.
+	if err := ValidateType(fu.Type); err != nil {
+		return err
+	}
+
+	// Check allowed types.
+	if len(fu.Properties.AllowedTypes) == 0 {
+		return errors.New("property allowed types must not be empty")
+	}
+	for _, v := range fu.Properties.AllowedTypes {
+		if !strings.Contains(v, "/") {
+			return errors.New("invalid property allowed types, must be MIME types")
+		}
+	}
+
+	// Check max size and count.
+	if fu.Properties.MaxSize <= 0 {
+		return errors.New("invalid property max size, must be greater than 0")
+	}
+	if fu.Properties.MaxCount <= 0 {
+		return errors.New("invalid property max count, must be greater than 0")
+	}
+
+	return nil
+}
+
+// ValidateAnswer implements the AnswerValidator interface. The answer is
+// expected to be an array of blob IDs returned by the upload endpoint.
+func (fu *FileUpload) ValidateAnswer(answer interface{}) error {
+	// Get the answer as a slice.
+	var ids []interface{}
+	if answer != nil {
+		var ok bool
+		if ids, ok = answer.([]interface{}); !ok {
+			return fmt.Errorf("%s must be an array of file IDs", fu.Name)
+		}
+	}
+
+	// Handle empty answers.
+	if len(ids) == 0 {
+		if fu.Properties.Required {
+			return fmt.Errorf("%s is required", fu.Name)
+		}
+		return nil
+	}
+
+	// Check count.
+	if len(ids) > fu.Properties.MaxCount {
+		return fmt.Errorf("%s must not have more than %d files", fu.Name, fu.Properties.MaxCount)
+	}
+
+	// Check IDs.
+	seen := map[string]bool{}
+	for _, v := range ids {
+		id, ok := v.(string)
+		if !ok || id == "" {
+			return fmt.Errorf("%s must be an array of file IDs", fu.Name)
+		}
+		if seen[id] {
+			return fmt.Errorf("%s contains a duplicate file", fu.Name)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// AllowsType checks if the given content type is allowed. Allowed types may
+// use a wildcard subtype, such as "image/*".
+func (fu *FileUpload) AllowsType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, v := range fu.Properties.AllowedTypes {
+		if v == mediaType {
+			return true
+		}
+		if strings.HasSuffix(v, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(v, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FileUploadProperties defines the file upload module properties.
+type FileUploadProperties struct {
+	Label        string   `json:"label"`
+	Sublabel     string   `json:"sublabel"`
+	Tooltip      string   `json:"tooltip"`
+	Required     bool     `json:"required"`
+	AllowedTypes []string `json:"allowed_types"`
+	MaxSize      int      `json:"max_size"`
+	MaxCount     int      `json:"max_count"`
+}
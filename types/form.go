@@ -1,7 +1,16 @@
 package types
 
-// Form defines a form.
+// Form defines a form. Revision is the number of the revision the modules
+// and pricing settings are from, either the working draft or the published
+// revision customers see. UnpublishedChanges is set when the draft has
+// changed since it was last published. Version is incremented on every
+// update, and updates must be made against the current version.
 type Form struct {
-	ID      string
-	Modules []Module
+	ID                 string
+	Modules            []Module
+	Pricing            *Pricing
+	Revision           int
+	PublishedRevision  int
+	UnpublishedChanges bool
+	Version            int
 }
@@ -0,0 +1,236 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Module change kinds.
+const (
+	ModuleAdded   = "added"
+	ModuleRemoved = "removed"
+	ModuleChanged = "changed"
+	ModuleMoved   = "moved"
+)
+
+// FormRevision defines a revision of a form, its definition as it was saved
+// by the author. Every update of a form creates a new revision, numbered
+// from 1.
+type FormRevision struct {
+	FormID  string
+	Number  int
+	Author  string
+	Form    *Form
+	Created time.Time
+}
+
+// FormDiff defines the differences between two revisions of a form.
+// Pricing is set when the pricing settings changed.
+type FormDiff struct {
+	From    int
+	To      int
+	Modules []ModuleChange
+	Pricing bool
+}
+
+// ModuleChange defines a change to a module between two revisions of a
+// form. Modules are matched by ID, so a renamed module is changed rather
+// than removed and added. Fields lists the changed fields of changed
+// modules, such as "name" or "properties.label".
+type ModuleChange struct {
+	ID     string
+	Name   string
+	Type   string
+	Change string
+	Fields []string
+}
+
+// moduleEntry defines a module of a form with the ID it is matched by, its
+// parent ID and its position among the siblings in both revisions.
+type moduleEntry struct {
+	key      string
+	module   Module
+	parent   string
+	position int
+	fields   map[string]interface{}
+}
+
+// DiffForms returns the differences between the from and to revisions of a
+// form. Removed modules are listed first, followed by the added, changed and
+// moved modules in the order of the to revision.
+func DiffForms(from, to *FormRevision) (*FormDiff, error) {
+	d := &FormDiff{
+		From:    from.Number,
+		To:      to.Number,
+		Modules: []ModuleChange{},
+	}
+
+	// Get the modules of both revisions.
+	a, err := moduleEntries(from.Form.Modules)
+	if err != nil {
+		return nil, err
+	}
+	b, err := moduleEntries(to.Form.Modules)
+	if err != nil {
+		return nil, err
+	}
+	aKeys, bKeys := map[string]bool{}, map[string]bool{}
+	aByKey := map[string]*moduleEntry{}
+	for _, v := range a {
+		aKeys[v.key] = true
+		aByKey[v.key] = v
+	}
+	for _, v := range b {
+		bKeys[v.key] = true
+	}
+
+	// Number the modules among the siblings in both revisions, so adding or
+	// removing a module doesn't move the ones after it.
+	setPositions(a, bKeys)
+	setPositions(b, aKeys)
+
+	// Handle the removed modules.
+	for _, v := range a {
+		if !bKeys[v.key] {
+			d.Modules = append(d.Modules, ModuleChange{
+				ID:     v.key,
+				Name:   v.module.GetName(),
+				Type:   v.module.GetType(),
+				Change: ModuleRemoved,
+			})
+		}
+	}
+
+	// Handle the added, changed and moved modules.
+	for _, v := range b {
+		change := ModuleChange{
+			ID:   v.key,
+			Name: v.module.GetName(),
+			Type: v.module.GetType(),
+		}
+		old, ok := aByKey[v.key]
+		switch {
+		case !ok:
+			change.Change = ModuleAdded
+		case !reflect.DeepEqual(old.fields, v.fields):
+			change.Change = ModuleChanged
+			change.Fields = diffFields(old.fields, v.fields)
+		case old.parent != v.parent || old.position != v.position:
+			change.Change = ModuleMoved
+		default:
+			continue
+		}
+		d.Modules = append(d.Modules, change)
+	}
+
+	// Check the pricing settings.
+	pa, err := toMap(from.Form.Pricing)
+	if err != nil {
+		return nil, err
+	}
+	pb, err := toMap(to.Form.Pricing)
+	if err != nil {
+		return nil, err
+	}
+	d.Pricing = !reflect.DeepEqual(pa, pb)
+
+	return d, nil
+}
+
+// moduleEntries returns the given modules and their nested child modules,
+// depth first, keyed by their IDs.
+func moduleEntries(modules []Module) ([]*moduleEntry, error) {
+	entries := []*moduleEntry{}
+
+	var walk func(modules []Module, parent string) error
+	walk = func(modules []Module, parent string) error {
+		for _, module := range modules {
+			// Get the fields, leaving out the ID and the children, which
+			// are compared on their own.
+			fields, err := toMap(module)
+			if err != nil {
+				return err
+			}
+			delete(fields, "id")
+			delete(fields, "modules")
+
+			entries = append(entries, &moduleEntry{
+				key:    module.GetID(),
+				module: module,
+				parent: parent,
+				fields: fields,
+			})
+			if p, ok := module.(Parent); ok {
+				if err := walk(p.Children(), module.GetID()); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+	if err := walk(modules, ""); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// setPositions numbers the given entries among their siblings, counting
+// only the siblings with a key in the given keys.
+func setPositions(entries []*moduleEntry, keys map[string]bool) {
+	counts := map[string]int{}
+	for _, v := range entries {
+		if !keys[v.key] {
+			continue
+		}
+		v.position = counts[v.parent]
+		counts[v.parent]++
+	}
+}
+
+// diffFields returns the sorted keys of the fields that differ between a
+// and b. Properties are compared one by one.
+func diffFields(a, b map[string]interface{}) []string {
+	fields := []string{}
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	for k := range keys {
+		if reflect.DeepEqual(a[k], b[k]) {
+			continue
+		}
+		pa, aOK := a[k].(map[string]interface{})
+		pb, bOK := b[k].(map[string]interface{})
+		if k != "properties" || !aOK || !bOK {
+			fields = append(fields, k)
+			continue
+		}
+		for _, v := range diffFields(pa, pb) {
+			fields = append(fields, k+"."+v)
+		}
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// toMap returns the given value as a JSON object.
+func toMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
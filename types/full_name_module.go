@@ -13,11 +13,21 @@ func (fn *FullName) SetID(id string) {
 	fn.ID = id
 }
 
+// GetID implements the Module interface.
+func (fn *FullName) GetID() string {
+	return fn.ID
+}
+
 // GetType implements the Module interface.
 func (fn *FullName) GetType() string {
 	return fn.Type
 }
 
+// GetName implements the Module interface.
+func (fn *FullName) GetName() string {
+	return fn.Name
+}
+
 // Validate implements the Module interface.
 func (fn *FullName) Validate() error {
 	// Check type.
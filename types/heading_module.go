@@ -1,5 +1,7 @@
 package types
 
+import "errors"
+
 // Heading defines the heading module.
 type Heading struct {
 	ID         string            `json:"id"`
@@ -13,11 +15,21 @@ func (h *Heading) SetID(id string) {
 	h.ID = id
 }
 
+// GetID implements the Module interface.
+func (h *Heading) GetID() string {
+	return h.ID
+}
+
 // GetType implements the Module interface.
 func (h *Heading) GetType() string {
 	return h.Type
 }
 
+// GetName implements the Module interface.
+func (h *Heading) GetName() string {
+	return h.Name
+}
+
 // Validate implements the Module interface.
 func (h *Heading) Validate() error {
 	// Check type.
@@ -25,6 +37,11 @@ func (h *Heading) Validate() error {
 		return err
 	}
 
+	// Check image.
+	if h.Properties.Image != "" && !ValidAssetID(h.Properties.Image) {
+		return errors.New("invalid property image, must be an asset ID")
+	}
+
 	return nil
 }
 
@@ -36,6 +53,7 @@ type HeadingProperties struct {
 	Alignment         string `json:"alignment"`
 	ImageAlignment    string `json:"image_alignment"`
 	VerticalAlignment string `json:"vertical_alignment"`
-	// Image string `json:"image"`
-	ImageWidth int `json:"image_width"`
+	Image             string `json:"image"`
+	ImageURL          string `json:"image_url"`
+	ImageWidth        int    `json:"image_width"`
 }
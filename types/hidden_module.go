@@ -0,0 +1,90 @@
+package types
+
+import (
+	"errors"
+	"net/url"
+
+	"estimator/utils"
+)
+
+// HiddenSources defines the available value sources for a hidden module.
+var HiddenSources []string = []string{
+	"query",
+	"referrer",
+	"constant",
+}
+
+// Hidden defines the hidden module. Its value is never entered by the
+// customer, it is resolved from the source when the form is rendered and
+// again when it is submitted.
+type Hidden struct {
+	ID         string           `json:"id"`
+	Type       string           `json:"type"`
+	Name       string           `json:"name"`
+	Properties HiddenProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (h *Hidden) SetID(id string) {
+	h.ID = id
+}
+
+// GetID implements the Module interface.
+func (h *Hidden) GetID() string {
+	return h.ID
+}
+
+// GetType implements the Module interface.
+func (h *Hidden) GetType() string {
+	return h.Type
+}
+
+// GetName implements the Module interface.
+func (h *Hidden) GetName() string {
+	return h.Name
+}
+
+// Validate implements the Module interface.
+func (h *Hidden) Validate() error {
+	// Check type.
+	if err := ValidateType(h.Type); err != nil {
+		return err
+	}
+
+	// Check source.
+	if !utils.SliceContains(HiddenSources, h.Properties.Source) {
+		return errors.New("invalid property source")
+	}
+
+	// Check param.
+	if h.Properties.Source == "query" && h.Properties.Param == "" {
+		return errors.New("property param is required for the query source")
+	}
+
+	return nil
+}
+
+// Prefill implements the Prefiller interface. The value property is used as
+// the fallback when the query parameter or referrer is missing.
+func (h *Hidden) Prefill(params url.Values, referrer string) []string {
+	switch h.Properties.Source {
+	case "query":
+		if params.Has(h.Properties.Param) {
+			h.Properties.Value = params.Get(h.Properties.Param)
+			return []string{h.Properties.Param}
+		}
+	case "referrer":
+		if referrer != "" {
+			h.Properties.Value = referrer
+		}
+	}
+
+	return nil
+}
+
+// HiddenProperties defines the hidden module properties.
+type HiddenProperties struct {
+	Source string `json:"source"`
+	Param  string `json:"param"`
+	Value  string `json:"value"`
+}
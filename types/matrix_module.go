@@ -0,0 +1,247 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"estimator/utils"
+)
+
+// MatrixCellTypes defines the available cell types for a matrix module.
+var MatrixCellTypes []string = []string{
+	"single-select",
+	"multi-select",
+	"number",
+}
+
+// Matrix defines the matrix module.
+type Matrix struct {
+	ID         string           `json:"id"`
+	Type       string           `json:"type"`
+	Name       string           `json:"name"`
+	Properties MatrixProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (mx *Matrix) SetID(id string) {
+	mx.ID = id
+}
+
+// GetID implements the Module interface.
+func (mx *Matrix) GetID() string {
+	return mx.ID
+}
+
+// GetType implements the Module interface.
+func (mx *Matrix) GetType() string {
+	return mx.Type
+}
+
+// GetName implements the Module interface.
+func (mx *Matrix) GetName() string {
+	return mx.Name
+}
+
+// Validate implements the Module interface.
+func (mx *Matrix) Validate() error {
+	// Check type.
+	if err := ValidateType(mx.Type); err != nil {
+		return err
+	}
+
+	// Check cell type.
+	if !utils.SliceContains(MatrixCellTypes, mx.Properties.CellType) {
+		return errors.New("invalid property cell type")
+	}
+
+	// Check rows.
+	if len(mx.Properties.Rows) == 0 {
+		return errors.New("property rows must not be empty")
+	}
+	rowIDs := map[string]bool{}
+	for _, v := range mx.Properties.Rows {
+		if v.ID == "" || rowIDs[v.ID] {
+			return errors.New("invalid row, ID must be unique and not empty")
+		}
+		rowIDs[v.ID] = true
+		if err := ValidatePriceRange(v.Price, v.MinPrice, v.MaxPrice); err != nil {
+			return fmt.Errorf("invalid row %s, %v", v.ID, err)
+		}
+	}
+
+	// Check columns.
+	if len(mx.Properties.Columns) == 0 {
+		return errors.New("property columns must not be empty")
+	}
+	columnIDs := map[string]bool{}
+	for _, v := range mx.Properties.Columns {
+		if v.ID == "" || columnIDs[v.ID] {
+			return errors.New("invalid column, ID must be unique and not empty")
+		}
+		columnIDs[v.ID] = true
+	}
+
+	// Check number range.
+	if mx.Properties.CellType == "number" && mx.Properties.Max <= mx.Properties.Min {
+		return errors.New("property max must be greater than min")
+	}
+
+	return nil
+}
+
+// ValidateAnswer implements the AnswerValidator interface. The answer is
+// expected to be an object keyed by row ID, where each row answer is a
+// column ID for single-select cells, an array of column IDs for
+// multi-select cells, or an object of numbers keyed by column ID for number
+// cells.
+func (mx *Matrix) ValidateAnswer(answer interface{}) error {
+	// Get the answer as a map.
+	var rows map[string]interface{}
+	if answer != nil {
+		var ok bool
+		if rows, ok = answer.(map[string]interface{}); !ok {
+			return fmt.Errorf("%s must be an object keyed by row", mx.Name)
+		}
+	}
+
+	// Check for unknown rows.
+	for rowID := range rows {
+		if mx.row(rowID) == nil {
+			return fmt.Errorf("%s has an unknown row %s", mx.Name, rowID)
+		}
+	}
+
+	// Loop through the rows.
+	for _, row := range mx.Properties.Rows {
+		// Handle empty rows.
+		v, ok := rows[row.ID]
+		if !ok || v == nil {
+			if mx.Properties.Required {
+				return fmt.Errorf("%s[%s] is required", mx.Name, row.ID)
+			}
+			continue
+		}
+
+		// Check the cells.
+		if err := mx.validateRow(row.ID, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRow validates the answer for a single row.
+func (mx *Matrix) validateRow(rowID string, v interface{}) error {
+	switch mx.Properties.CellType {
+	case "single-select":
+		columnID, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s[%s] must be a column ID", mx.Name, rowID)
+		}
+		if mx.column(columnID) == nil {
+			return fmt.Errorf("%s[%s] has an unknown column %s", mx.Name, rowID, columnID)
+		}
+	case "multi-select":
+		columnIDs, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s[%s] must be an array of column IDs", mx.Name, rowID)
+		}
+		if len(columnIDs) == 0 && mx.Properties.Required {
+			return fmt.Errorf("%s[%s] is required", mx.Name, rowID)
+		}
+		seen := map[string]bool{}
+		for _, c := range columnIDs {
+			columnID, ok := c.(string)
+			if !ok {
+				return fmt.Errorf("%s[%s] must be an array of column IDs", mx.Name, rowID)
+			}
+			if mx.column(columnID) == nil {
+				return fmt.Errorf("%s[%s] has an unknown column %s", mx.Name, rowID, columnID)
+			}
+			if seen[columnID] {
+				return fmt.Errorf("%s[%s][%s] is selected more than once", mx.Name, rowID, columnID)
+			}
+			seen[columnID] = true
+		}
+	case "number":
+		cells, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s[%s] must be an object keyed by column", mx.Name, rowID)
+		}
+		for columnID, c := range cells {
+			if mx.column(columnID) == nil {
+				return fmt.Errorf("%s[%s] has an unknown column %s", mx.Name, rowID, columnID)
+			}
+			n, ok := c.(float64)
+			if !ok {
+				return fmt.Errorf("%s[%s][%s] must be a number", mx.Name, rowID, columnID)
+			}
+			if n < mx.Properties.Min || n > mx.Properties.Max {
+				return fmt.Errorf("%s[%s][%s] must be between %g and %g", mx.Name, rowID, columnID, mx.Properties.Min, mx.Properties.Max)
+			}
+		}
+		if mx.Properties.Required {
+			for _, column := range mx.Properties.Columns {
+				if _, ok := cells[column.ID]; !ok {
+					return fmt.Errorf("%s[%s][%s] is required", mx.Name, rowID, column.ID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// row gets the row with the given ID.
+func (mx *Matrix) row(id string) *MatrixRow {
+	for i, v := range mx.Properties.Rows {
+		if v.ID == id {
+			return &mx.Properties.Rows[i]
+		}
+	}
+
+	return nil
+}
+
+// column gets the column with the given ID.
+func (mx *Matrix) column(id string) *MatrixColumn {
+	for i, v := range mx.Properties.Columns {
+		if v.ID == id {
+			return &mx.Properties.Columns[i]
+		}
+	}
+
+	return nil
+}
+
+// MatrixProperties defines the matrix module properties. Min and Max only
+// apply to number cells.
+type MatrixProperties struct {
+	Label    string         `json:"label"`
+	Sublabel string         `json:"sublabel"`
+	Tooltip  string         `json:"tooltip"`
+	Required bool           `json:"required"`
+	CellType string         `json:"cell_type"`
+	Rows     []MatrixRow    `json:"rows"`
+	Columns  []MatrixColumn `json:"columns"`
+	Min      float64        `json:"min"`
+	Max      float64        `json:"max"`
+}
+
+// MatrixRow defines a matrix row. Price is charged per selected cell, or per
+// unit entered in number cells. MinPrice and MaxPrice optionally give the
+// range of the price.
+type MatrixRow struct {
+	ID       string  `json:"id"`
+	Label    string  `json:"label"`
+	Price    Decimal `json:"price"`
+	MinPrice Decimal `json:"min_price"`
+	MaxPrice Decimal `json:"max_price"`
+}
+
+// MatrixColumn defines a matrix column.
+type MatrixColumn struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
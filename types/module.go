@@ -2,6 +2,8 @@ package types
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
 
 	"estimator/utils"
 )
@@ -12,15 +14,138 @@ var ModuleTypes []string = []string{
 	"multiple-choice",
 	"heading",
 	"full-name",
+	"date",
+	"time",
+	"date-range",
+	"file-upload",
+	"slider",
+	"rating",
+	"matrix",
+	"hidden",
+	"signature",
+	"consent",
+	"page-break",
+	"product-list",
+	"section",
+	"repeater",
 }
 
+// ErrModuleNotFound is returned when a module does not exist in a form.
+var ErrModuleNotFound = errors.New("module could not be found")
+
 // Module defines the module interface.
 type Module interface {
 	SetID(id string)
+	GetID() string
 	GetType() string
+	GetName() string
 	Validate() error
 }
 
+// AnswerValidator defines the interface for modules that accept an answer
+// when a form is submitted.
+type AnswerValidator interface {
+	ValidateAnswer(answer interface{}) error
+}
+
+// Prefiller defines the interface for modules whose value can be prefilled
+// from allow-listed URL query parameters or the referrer. Prefill returns the
+// query parameters that were used.
+type Prefiller interface {
+	Prefill(params url.Values, referrer string) []string
+}
+
+// Parent defines the interface for modules that hold child modules.
+type Parent interface {
+	Children() []Module
+}
+
+// Container defines the interface for parent modules whose children are
+// answered alongside the other modules of the form. Visible reports whether
+// the children are shown for the given answers, with conditions on dates
+// evaluated in the given time zones.
+type Container interface {
+	Parent
+	Visible(answers map[string]interface{}, locations Locations) bool
+}
+
+// AllModules returns the given modules and all of their nested child
+// modules, including those answered per repeater item, depth first.
+func AllModules(modules []Module) []Module {
+	all := []Module{}
+	for _, module := range modules {
+		all = append(all, module)
+		if p, ok := module.(Parent); ok {
+			all = append(all, AllModules(p.Children())...)
+		}
+	}
+
+	return all
+}
+
+// Flatten returns the given modules and the nested child modules of
+// containers, depth first. Children of repeaters are not included, as they
+// are answered per item.
+func Flatten(modules []Module) []Module {
+	flat := []Module{}
+	for _, module := range modules {
+		flat = append(flat, module)
+		if c, ok := module.(Container); ok {
+			flat = append(flat, Flatten(c.Children())...)
+		}
+	}
+
+	return flat
+}
+
+// ValidateNames checks the given modules and the nested child modules of
+// containers have unique names, as answers, formulas and conditions refer to
+// modules by name. The children of each repeater are checked among
+// themselves, as they are answered per item.
+func ValidateNames(modules []Module) error {
+	names := make(map[string]bool)
+	for _, module := range Flatten(modules) {
+		// Check the name.
+		name := module.GetName()
+		if names[name] {
+			return fmt.Errorf("duplicate module name %s", name)
+		}
+		names[name] = true
+
+		// Check the children answered per item.
+		if _, ok := module.(Container); ok {
+			continue
+		}
+		if p, ok := module.(Parent); ok {
+			if err := ValidateNames(p.Children()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// VisibleModules returns the given modules and the nested child modules of
+// containers that are visible for the given answers, depth first.
+func VisibleModules(modules []Module, answers map[string]interface{}) []Module {
+	return visibleModules(modules, answers, DateLocations(modules))
+}
+
+// visibleModules returns the visible modules as VisibleModules does, with
+// conditions on dates evaluated in the given time zones.
+func visibleModules(modules []Module, answers map[string]interface{}, locations Locations) []Module {
+	visible := []Module{}
+	for _, module := range modules {
+		visible = append(visible, module)
+		if c, ok := module.(Container); ok && c.Visible(answers, locations) {
+			visible = append(visible, visibleModules(c.Children(), answers, locations)...)
+		}
+	}
+
+	return visible
+}
+
 // ValidateType handles validating the module type.
 func ValidateType(t string) error {
 	if !utils.SliceContains(ModuleTypes, t) {
@@ -0,0 +1,347 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"estimator/utils"
+)
+
+// Rounding modes used when an amount has to be rounded to the minor unit of
+// its currency.
+const (
+	RoundHalfUp   = "half_up"
+	RoundHalfEven = "half_even"
+	RoundDown     = "down"
+	RoundUp       = "up"
+)
+
+// RoundingModes defines the available rounding modes. Half up rounds halves
+// away from zero, half even is banker's rounding, and down and up round
+// towards and away from zero.
+var RoundingModes []string = []string{
+	RoundHalfUp,
+	RoundHalfEven,
+	RoundDown,
+	RoundUp,
+}
+
+// currencies maps the supported ISO 4217 currency codes to the number of
+// digits of their minor unit.
+var currencies = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CNY": 2,
+	"DKK": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MXN": 2,
+	"NOK": 2,
+	"NZD": 2,
+	"PLN": 2,
+	"SEK": 2,
+	"SGD": 2,
+	"USD": 2,
+	"ZAR": 2,
+}
+
+// currencySymbols maps currency codes to the symbol used when formatting.
+// Currencies without a symbol are formatted with their code.
+var currencySymbols = map[string]string{
+	"AUD": "$",
+	"BRL": "R$",
+	"CAD": "$",
+	"CNY": "¥",
+	"EUR": "€",
+	"GBP": "£",
+	"HKD": "$",
+	"INR": "₹",
+	"JPY": "¥",
+	"KRW": "₩",
+	"MXN": "$",
+	"NZD": "$",
+	"SGD": "$",
+	"USD": "$",
+}
+
+// locale defines how amounts are formatted for a locale.
+type locale struct {
+	group       string
+	decimal     string
+	symbolAfter bool
+	space       bool
+}
+
+// locales defines the supported locales.
+var locales = map[string]locale{
+	"en-AU": {group: ",", decimal: "."},
+	"en-CA": {group: ",", decimal: "."},
+	"en-GB": {group: ",", decimal: "."},
+	"en-US": {group: ",", decimal: "."},
+	"ja-JP": {group: ",", decimal: "."},
+	"de-CH": {group: "'", decimal: ".", space: true},
+	"de-DE": {group: ".", decimal: ",", symbolAfter: true, space: true},
+	"es-ES": {group: ".", decimal: ",", symbolAfter: true, space: true},
+	"es-MX": {group: ",", decimal: "."},
+	"fr-CA": {group: " ", decimal: ",", symbolAfter: true, space: true},
+	"fr-FR": {group: " ", decimal: ",", symbolAfter: true, space: true},
+	"it-IT": {group: ".", decimal: ",", symbolAfter: true, space: true},
+	"nl-NL": {group: ".", decimal: ",", space: true},
+	"pt-BR": {group: ".", decimal: ",", space: true},
+	"sv-SE": {group: " ", decimal: ",", symbolAfter: true, space: true},
+}
+
+// DefaultCurrency defines the currency used when none is set.
+const DefaultCurrency = "USD"
+
+// DefaultLocale defines the locale used when none is set.
+const DefaultLocale = "en-US"
+
+// ValidCurrency checks if the given ISO 4217 currency code is supported.
+func ValidCurrency(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
+
+// ValidLocale checks if the given locale is supported.
+func ValidLocale(code string) bool {
+	_, ok := locales[code]
+	return ok
+}
+
+// ValidateRounding validates the given rounding mode.
+func ValidateRounding(mode string) error {
+	if !utils.SliceContains(RoundingModes, mode) {
+		return errors.New("invalid rounding mode")
+	}
+
+	return nil
+}
+
+// CurrencyDigits returns the number of digits of the minor unit of the
+// given currency.
+func CurrencyDigits(code string) int {
+	if d, ok := currencies[code]; ok {
+		return d
+	}
+
+	return 2
+}
+
+// Money defines an amount of money as a whole number of the minor unit of
+// its ISO 4217 currency, such as cents. In JSON it is a number with the
+// currency's decimal places.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney creates a new amount of money from a decimal, rounded to the
+// minor unit of the currency with the given rounding mode.
+func NewMoney(d Decimal, currency, mode string) (Money, error) {
+	return MoneyFromProduct(d, DecimalFromInt(1), currency, mode)
+}
+
+// MoneyFromProduct creates a new amount of money from the product of two
+// decimals, such as a unit price and a quantity. The product is rounded
+// once, to the minor unit of the currency with the given rounding mode.
+func MoneyFromProduct(a, b Decimal, currency, mode string) (Money, error) {
+	num := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(b)))
+	num.Mul(num, pow10(CurrencyDigits(currency)))
+	den := new(big.Int).Mul(big.NewInt(decimalScale), big.NewInt(decimalScale))
+
+	amount, err := toInt64(roundRat(num, den, mode))
+	return Money{Amount: amount, Currency: currency}, err
+}
+
+// Add returns the sum of the amounts, which must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	amount, err := toInt64(new(big.Int).Add(big.NewInt(m.Amount), big.NewInt(o.Amount)))
+	return Money{Amount: amount, Currency: m.Currency}, err
+}
+
+// Sub returns the difference of the amounts, which must be in the same
+// currency.
+func (m Money) Sub(o Money) (Money, error) {
+	amount, err := toInt64(new(big.Int).Sub(big.NewInt(m.Amount), big.NewInt(o.Amount)))
+	return Money{Amount: amount, Currency: m.Currency}, err
+}
+
+// Neg returns the negated amount.
+func (m Money) Neg() (Money, error) {
+	amount, err := toInt64(new(big.Int).Neg(big.NewInt(m.Amount)))
+	return Money{Amount: amount, Currency: m.Currency}, err
+}
+
+// MulFrac returns the amount multiplied by num/den, rounded with the given
+// rounding mode. A percentage is MulFrac(rate, 100).
+func (m Money) MulFrac(num, den Decimal, mode string) (Money, error) {
+	if den == 0 {
+		return Money{}, errors.New("can't divide by zero")
+	}
+	n := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(int64(num)))
+	amount, err := toInt64(roundRat(n, big.NewInt(int64(den)), mode))
+	return Money{Amount: amount, Currency: m.Currency}, err
+}
+
+// Convert returns the amount converted to the given currency at the given
+// exchange rate, rounded once to the minor unit of the currency with the
+// given rounding mode.
+func (m Money) Convert(rate Decimal, currency, mode string) (Money, error) {
+	num := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(int64(rate)))
+	num.Mul(num, pow10(CurrencyDigits(currency)))
+	den := new(big.Int).Mul(big.NewInt(decimalScale), pow10(CurrencyDigits(m.Currency)))
+
+	amount, err := toInt64(roundRat(num, den, mode))
+	return Money{Amount: amount, Currency: currency}, err
+}
+
+// Min returns the smaller of the amounts.
+func (m Money) Min(o Money) Money {
+	if o.Amount < m.Amount {
+		return o
+	}
+	return m
+}
+
+// Max returns the larger of the amounts.
+func (m Money) Max(o Money) Money {
+	if o.Amount > m.Amount {
+		return o
+	}
+	return m
+}
+
+// IsZero checks if the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Decimal returns the amount as a decimal in the major unit.
+func (m Money) Decimal() (Decimal, error) {
+	digits := CurrencyDigits(m.Currency)
+	v, err := toInt64(new(big.Int).Mul(big.NewInt(m.Amount), pow10(DecimalPlaces-digits)))
+	return Decimal(v), err
+}
+
+// String returns the amount in the major unit with the currency's decimal
+// places, such as "1234.50".
+func (m Money) String() string {
+	return formatMinor(m.Amount, CurrencyDigits(m.Currency), "", ".")
+}
+
+// Format returns the amount formatted for the given locale with the
+// currency symbol, such as "$1,234.50" for en-US or "1.234,50 €" for de-DE.
+// Unsupported locales are formatted as en-US.
+func (m Money) Format(code string) string {
+	l, ok := locales[code]
+	if !ok {
+		l = locales[DefaultLocale]
+	}
+
+	// Get the symbol.
+	symbol, ok := currencySymbols[m.Currency]
+	space := l.space
+	if !ok {
+		symbol, space = m.Currency, true
+	}
+	sep := ""
+	if space {
+		sep = " "
+	}
+
+	// Format the number without the sign.
+	amount, sign := m.Amount, ""
+	if amount < 0 {
+		amount, sign = -amount, "-"
+	}
+	n := formatMinor(amount, CurrencyDigits(m.Currency), l.group, l.decimal)
+
+	if l.symbolAfter {
+		return sign + n + sep + symbol
+	}
+	return sign + symbol + sep + n
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// formatMinor formats an amount in minor units with the given number of
+// decimal places, group separator and decimal separator.
+func formatMinor(amount int64, digits int, group, decimal string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	// Split the whole and fractional parts.
+	s := strconv.FormatInt(amount, 10)
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-digits], s[len(s)-digits:]
+
+	// Group the thousands.
+	if group != "" {
+		for i := len(whole) - 3; i > 0; i -= 3 {
+			whole = whole[:i] + group + whole[i:]
+		}
+	}
+
+	if digits == 0 {
+		return sign + whole
+	}
+	return sign + whole + decimal + frac
+}
+
+// pow10 returns 10 to the power of n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundRat returns num/den rounded to a whole number with the given
+// rounding mode. Unknown modes round half up.
+func roundRat(num, den *big.Int, mode string) *big.Int {
+	// Work with positive values.
+	neg := (num.Sign() < 0) != (den.Sign() < 0)
+	n := new(big.Int).Abs(num)
+	d := new(big.Int).Abs(den)
+
+	// Divide, keeping the remainder.
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() != 0 {
+		// Compare twice the remainder to the divisor to find halves.
+		cmp := new(big.Int).Lsh(r, 1).Cmp(d)
+		switch mode {
+		case RoundDown:
+		case RoundUp:
+			q.Add(q, big.NewInt(1))
+		case RoundHalfEven:
+			if cmp > 0 || (cmp == 0 && q.Bit(0) == 1) {
+				q.Add(q, big.NewInt(1))
+			}
+		default:
+			if cmp >= 0 {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+
+	if neg {
+		q.Neg(q)
+	}
+	return q
+}
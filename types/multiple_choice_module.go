@@ -1,5 +1,11 @@
 package types
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
 // MultipleChoice defines the multiple choice module.
 type MultipleChoice struct {
 	ID         string                   `json:"id"`
@@ -13,11 +19,21 @@ func (mc *MultipleChoice) SetID(id string) {
 	mc.ID = id
 }
 
+// GetID implements the Module interface.
+func (mc *MultipleChoice) GetID() string {
+	return mc.ID
+}
+
 // GetType implements the Module interface.
 func (mc *MultipleChoice) GetType() string {
 	return mc.Type
 }
 
+// GetName implements the Module interface.
+func (mc *MultipleChoice) GetName() string {
+	return mc.Name
+}
+
 // Validate implements the Module interface.
 func (mc *MultipleChoice) Validate() error {
 	// Check type.
@@ -25,24 +41,87 @@ func (mc *MultipleChoice) Validate() error {
 		return err
 	}
 
+	// Check option prices.
+	for _, v := range mc.Properties.Options {
+		if err := ValidatePriceRange(v.Price, v.MinPrice, v.MaxPrice); err != nil {
+			return fmt.Errorf("invalid option %s price, %v", v.Value, err)
+		}
+	}
+
+	// Check default.
+	if mc.Properties.Default != "" && len(mc.Selected(mc.Properties.Default)) == 0 {
+		return errors.New("invalid property default, must be the value of an option")
+	}
+
 	return nil
 }
 
+// Prefill implements the Prefiller interface, overriding the default with
+// the prefill parameter when one is set and holds the value of an option.
+func (mc *MultipleChoice) Prefill(params url.Values, referrer string) []string {
+	if mc.Properties.PrefillParam == "" || !params.Has(mc.Properties.PrefillParam) {
+		return nil
+	}
+
+	v := params.Get(mc.Properties.PrefillParam)
+	if len(mc.Selected(v)) == 0 {
+		return nil
+	}
+	mc.Properties.Default = v
+
+	return []string{mc.Properties.PrefillParam}
+}
+
+// Selected returns the options selected by the given answer, which is the
+// value of an option or an array of option values.
+func (mc *MultipleChoice) Selected(answer interface{}) []MultipleChoiceOption {
+	// Get the selected values.
+	values := map[string]bool{}
+	switch a := answer.(type) {
+	case string:
+		values[a] = true
+	case []interface{}:
+		for _, v := range a {
+			if s, ok := v.(string); ok {
+				values[s] = true
+			}
+		}
+	}
+
+	// Get the options in the module order.
+	selected := []MultipleChoiceOption{}
+	for _, v := range mc.Properties.Options {
+		if values[v.Value] {
+			selected = append(selected, v)
+		}
+	}
+
+	return selected
+}
+
 // MultipleChoiceProperties defines the multiple choice module properties.
+// Default is the value of the option selected to start with.
 type MultipleChoiceProperties struct {
-	Label       string                 `json:"label"`
-	Sublabel    string                 `json:"sublabel"`
-	Tooltip     string                 `json:"tooltip"`
-	Required    bool                   `json:"required"`
-	Placeholder string                 `json:"placeholder"`
-	Suffix      string                 `json:"suffix"`
-	WidthType   bool                   `json:"width_type"`
-	Width       int                    `json:"width"`
-	Options     []MultipleChoiceOption `json:"options"`
-}
-
-// MultipleChoiceOption defines a multiple choice option.
+	Label        string                 `json:"label"`
+	Sublabel     string                 `json:"sublabel"`
+	Tooltip      string                 `json:"tooltip"`
+	Required     bool                   `json:"required"`
+	Placeholder  string                 `json:"placeholder"`
+	Suffix       string                 `json:"suffix"`
+	WidthType    bool                   `json:"width_type"`
+	Width        int                    `json:"width"`
+	Options      []MultipleChoiceOption `json:"options"`
+	Default      string                 `json:"default"`
+	PrefillParam string                 `json:"prefill_param"`
+}
+
+// MultipleChoiceOption defines a multiple choice option. Price is charged
+// when the option is selected, and MinPrice and MaxPrice optionally give
+// its range.
 type MultipleChoiceOption struct {
-	ID    string `json:"id"`
-	Value string `json:"value"`
+	ID       string  `json:"id"`
+	Value    string  `json:"value"`
+	Price    Decimal `json:"price"`
+	MinPrice Decimal `json:"min_price"`
+	MaxPrice Decimal `json:"max_price"`
 }
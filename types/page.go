@@ -0,0 +1,71 @@
+package types
+
+const (
+	// DefaultNextLabel defines the next button label used when a page break
+	// does not set one.
+	DefaultNextLabel = "Next"
+
+	// DefaultBackLabel defines the back button label used when a page break
+	// does not set one.
+	DefaultBackLabel = "Back"
+)
+
+// Page defines a page of a form. Pages are split by page break modules,
+// which are not included in the page modules.
+type Page struct {
+	Number    int
+	Title     string
+	NextLabel string
+	BackLabel string
+	Progress  int
+	Modules   []Module
+}
+
+// Pages splits the form modules into pages. A form without page breaks has
+// a single page. Progress is the percentage of the form completed once the
+// page is done.
+func (f *Form) Pages() []*Page {
+	pages := []*Page{}
+	page := &Page{
+		Modules: []Module{},
+	}
+
+	// Loop through the modules.
+	for _, module := range f.Modules {
+		pb, ok := module.(*PageBreak)
+		if !ok {
+			page.Modules = append(page.Modules, module)
+			continue
+		}
+
+		// A page break before any module titles the first page instead of
+		// leaving it empty.
+		if len(pages) > 0 || len(page.Modules) > 0 {
+			page.NextLabel = pb.Properties.NextLabel
+			pages = append(pages, page)
+			page = &Page{
+				Modules: []Module{},
+			}
+		}
+		page.Title = pb.Properties.Title
+		page.BackLabel = pb.Properties.BackLabel
+	}
+	pages = append(pages, page)
+
+	// Number the pages and fill in defaults.
+	for i, v := range pages {
+		v.Number = i + 1
+		v.Progress = v.Number * 100 / len(pages)
+		if v.NextLabel == "" && v.Number < len(pages) {
+			v.NextLabel = DefaultNextLabel
+		}
+		switch {
+		case v.Number == 1:
+			v.BackLabel = ""
+		case v.BackLabel == "":
+			v.BackLabel = DefaultBackLabel
+		}
+	}
+
+	return pages
+}
@@ -0,0 +1,49 @@
+package types
+
+// PageBreak defines the page break module, which starts a new page of the
+// form.
+type PageBreak struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	Name       string              `json:"name"`
+	Properties PageBreakProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (pb *PageBreak) SetID(id string) {
+	pb.ID = id
+}
+
+// GetID implements the Module interface.
+func (pb *PageBreak) GetID() string {
+	return pb.ID
+}
+
+// GetType implements the Module interface.
+func (pb *PageBreak) GetType() string {
+	return pb.Type
+}
+
+// GetName implements the Module interface.
+func (pb *PageBreak) GetName() string {
+	return pb.Name
+}
+
+// Validate implements the Module interface.
+func (pb *PageBreak) Validate() error {
+	// Check type.
+	if err := ValidateType(pb.Type); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PageBreakProperties defines the page break module properties. NextLabel is
+// the label of the button leading to the new page, and BackLabel the label
+// of the button on the new page leading back.
+type PageBreakProperties struct {
+	Title     string `json:"title"`
+	NextLabel string `json:"next_label"`
+	BackLabel string `json:"back_label"`
+}
@@ -0,0 +1,107 @@
+package types
+
+import "errors"
+
+// PriceRange defines an amount that can't be known exactly up front, as a
+// low, expected and high amount of money in the same currency.
+type PriceRange struct {
+	Low      Money `json:"low"`
+	Expected Money `json:"expected"`
+	High     Money `json:"high"`
+}
+
+// NewPriceRange creates a new price range for a quantity of a unit price
+// with an optional minimum and maximum, rounded to the minor unit of the
+// currency. When both the minimum and maximum are zero the price is exact.
+func NewPriceRange(price, min, max, quantity Decimal, currency, mode string) (PriceRange, error) {
+	if min == 0 && max == 0 {
+		min, max = price, price
+	}
+
+	var pr PriceRange
+	var err error
+	if pr.Low, err = MoneyFromProduct(min, quantity, currency, mode); err != nil {
+		return PriceRange{}, err
+	}
+	if pr.Expected, err = MoneyFromProduct(price, quantity, currency, mode); err != nil {
+		return PriceRange{}, err
+	}
+	if pr.High, err = MoneyFromProduct(max, quantity, currency, mode); err != nil {
+		return PriceRange{}, err
+	}
+
+	return pr, nil
+}
+
+// ExactPriceRange creates a new price range where the low, expected and
+// high amounts are the same.
+func ExactPriceRange(m Money) PriceRange {
+	return PriceRange{Low: m, Expected: m, High: m}
+}
+
+// ValidatePriceRange validates a price and its optional minimum and maximum,
+// which must surround the price when set.
+func ValidatePriceRange(price, min, max Decimal) error {
+	if price < 0 {
+		return errors.New("price must not be negative")
+	}
+	if min == 0 && max == 0 {
+		return nil
+	}
+	if min < 0 || min > price || max < price {
+		return errors.New("price range must have min <= price <= max")
+	}
+
+	return nil
+}
+
+// Add returns the sum of the price ranges.
+func (pr PriceRange) Add(o PriceRange) (PriceRange, error) {
+	return pr.combine(o, Money.Add)
+}
+
+// Sub returns the difference of the price ranges.
+func (pr PriceRange) Sub(o PriceRange) (PriceRange, error) {
+	return pr.combine(o, Money.Sub)
+}
+
+// Map returns the price range with the given function applied to each
+// amount.
+func (pr PriceRange) Map(fn func(Money) (Money, error)) (PriceRange, error) {
+	var res PriceRange
+	var err error
+	if res.Low, err = fn(pr.Low); err != nil {
+		return PriceRange{}, err
+	}
+	if res.Expected, err = fn(pr.Expected); err != nil {
+		return PriceRange{}, err
+	}
+	if res.High, err = fn(pr.High); err != nil {
+		return PriceRange{}, err
+	}
+
+	return res, nil
+}
+
+// combine returns the price range with the given function applied to each
+// amount and the matching amount of the other price range.
+func (pr PriceRange) combine(o PriceRange, fn func(Money, Money) (Money, error)) (PriceRange, error) {
+	var res PriceRange
+	var err error
+	if res.Low, err = fn(pr.Low, o.Low); err != nil {
+		return PriceRange{}, err
+	}
+	if res.Expected, err = fn(pr.Expected, o.Expected); err != nil {
+		return PriceRange{}, err
+	}
+	if res.High, err = fn(pr.High, o.High); err != nil {
+		return PriceRange{}, err
+	}
+
+	return res, nil
+}
+
+// IsZero checks if all of the amounts are zero.
+func (pr PriceRange) IsZero() bool {
+	return pr.Low.IsZero() && pr.Expected.IsZero() && pr.High.IsZero()
+}
@@ -0,0 +1,287 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"estimator/utils"
+	"estimator/utils/formula"
+)
+
+// AdjustmentTypes defines the available discount, coupon and fee types.
+var AdjustmentTypes []string = []string{
+	"percentage",
+	"fixed",
+}
+
+var (
+	// ErrCouponNotFound is returned when a coupon code does not exist.
+	ErrCouponNotFound = errors.New("coupon could not be found")
+
+	// ErrCouponNotValid is returned when a coupon is used outside of its
+	// validity window.
+	ErrCouponNotValid = errors.New("coupon is not valid at this time")
+
+	// ErrCurrencyNotAllowed is returned when an estimate is requested in a
+	// currency the form does not allow.
+	ErrCurrencyNotAllowed = errors.New("currency is not allowed")
+)
+
+// Pricing defines the form level pricing settings. Formulas add item lines
+// to the estimate, and the rest are applied to the estimate subtotal. Fees,
+// then discounts and the coupon, then the minimum charge are applied before
+// taxes. Prices of the form are in Currency, and amounts are
+// rounded to its minor unit with the Rounding mode. Estimates are formatted
+// for Locale, and can also be converted to any of the DisplayCurrencies.
+type Pricing struct {
+	Currency          string         `json:"currency"`
+	DisplayCurrencies []string       `json:"display_currencies"`
+	Rounding          string         `json:"rounding"`
+	Locale            string         `json:"locale"`
+	Formulas          []FormulaPrice `json:"formulas"`
+	Taxes             []Tax          `json:"taxes"`
+	Discounts         []Adjustment   `json:"discounts"`
+	Coupons           []Coupon       `json:"coupons"`
+	Fees              []Adjustment   `json:"fees"`
+	MinimumCharge     Decimal        `json:"minimum_charge"`
+}
+
+// GetCurrency gets the currency, defaulting to DefaultCurrency.
+func (p *Pricing) GetCurrency() string {
+	if p.Currency == "" {
+		return DefaultCurrency
+	}
+	return p.Currency
+}
+
+// GetRounding gets the rounding mode, defaulting to half up.
+func (p *Pricing) GetRounding() string {
+	if p.Rounding == "" {
+		return RoundHalfUp
+	}
+	return p.Rounding
+}
+
+// GetLocale gets the locale, defaulting to DefaultLocale.
+func (p *Pricing) GetLocale() string {
+	if p.Locale == "" {
+		return DefaultLocale
+	}
+	return p.Locale
+}
+
+// Validate validates the pricing settings.
+func (p *Pricing) Validate() error {
+	// Check currency, rounding and locale.
+	if !ValidCurrency(p.GetCurrency()) {
+		return errors.New("invalid currency, must be a supported ISO 4217 code")
+	}
+	if err := ValidateRounding(p.GetRounding()); err != nil {
+		return err
+	}
+	if !ValidLocale(p.GetLocale()) {
+		return errors.New("invalid locale")
+	}
+
+	// Check display currencies.
+	for _, v := range p.DisplayCurrencies {
+		if !ValidCurrency(v) {
+			return fmt.Errorf("invalid display currency %s", v)
+		}
+	}
+
+	// Check formulas.
+	for _, v := range p.Formulas {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid formula, %v", err)
+		}
+	}
+
+	// Check taxes.
+	for _, v := range p.Taxes {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Check discounts and fees.
+	for _, v := range p.Discounts {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid discount, %v", err)
+		}
+	}
+	for _, v := range p.Fees {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid fee, %v", err)
+		}
+	}
+
+	// Check coupons.
+	codes := map[string]bool{}
+	for _, v := range p.Coupons {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+		if codes[strings.ToUpper(v.Code)] {
+			return fmt.Errorf("duplicate coupon code %s", v.Code)
+		}
+		codes[strings.ToUpper(v.Code)] = true
+	}
+
+	// Check minimum charge.
+	if p.MinimumCharge < 0 {
+		return errors.New("minimum charge must not be negative")
+	}
+
+	return nil
+}
+
+// AllowsCurrency checks if estimates can be given in the given currency,
+// which is either the form currency or one of the display currencies.
+func (p *Pricing) AllowsCurrency(code string) bool {
+	return code == p.GetCurrency() || utils.SliceContains(p.DisplayCurrencies, code)
+}
+
+// Coupon gets the coupon with the given code, ignoring case, checking it is
+// valid at the given time. Usage limits are checked by the services.
+func (p *Pricing) Coupon(code string, now time.Time) (*Coupon, error) {
+	for i, v := range p.Coupons {
+		if !strings.EqualFold(v.Code, code) {
+			continue
+		}
+		if (!v.StartsAt.IsZero() && now.Before(v.StartsAt)) || (!v.EndsAt.IsZero() && !now.Before(v.EndsAt)) {
+			return nil, ErrCouponNotValid
+		}
+		return &p.Coupons[i], nil
+	}
+
+	return nil, ErrCouponNotFound
+}
+
+// FormulaPrice defines an estimate line charging Price per unit of the
+// result of Formula over the answers, such as "roof_area * 1.15". MinPrice
+// and MaxPrice optionally give the range of the price.
+type FormulaPrice struct {
+	Name     string  `json:"name"`
+	Formula  string  `json:"formula"`
+	Price    Decimal `json:"price"`
+	MinPrice Decimal `json:"min_price"`
+	MaxPrice Decimal `json:"max_price"`
+}
+
+// Validate validates the formula price.
+func (fp *FormulaPrice) Validate() error {
+	if fp.Name == "" {
+		return errors.New("name is required")
+	}
+	if _, err := formula.Parse(fp.Formula); err != nil {
+		return fmt.Errorf("formula %s, %v", fp.Name, err)
+	}
+	if err := ValidatePriceRange(fp.Price, fp.MinPrice, fp.MaxPrice); err != nil {
+		return fmt.Errorf("formula %s, %v", fp.Name, err)
+	}
+
+	return nil
+}
+
+// Tax defines a tax rate as a percentage. Inclusive taxes are already part
+// of the prices, so they are shown in the estimate without being added to
+// the total. PerLine taxes are calculated and rounded for each line instead
+// of once on the total.
+type Tax struct {
+	Name      string  `json:"name"`
+	Rate      Decimal `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+	PerLine   bool    `json:"per_line"`
+}
+
+// Validate validates the tax.
+func (t *Tax) Validate() error {
+	if t.Name == "" {
+		return errors.New("tax name is required")
+	}
+	if t.Rate < 0 || t.Rate > DecimalFromInt(100) {
+		return errors.New("tax rate must be between 0 and 100")
+	}
+
+	return nil
+}
+
+// Adjustment defines a discount or fee of either a percentage of the
+// subtotal or a fixed amount. It only applies when Condition is nil or
+// evaluates to true for the answers.
+type Adjustment struct {
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Value     Decimal    `json:"value"`
+	Condition *Condition `json:"condition"`
+}
+
+// Validate validates the adjustment.
+func (a *Adjustment) Validate() error {
+	if a.Name == "" {
+		return errors.New("name is required")
+	}
+	if err := validateAdjustmentValue(a.Type, a.Value); err != nil {
+		return err
+	}
+	if a.Condition != nil {
+		if err := a.Condition.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Coupon defines a discount the customer applies with a code. The coupon is
+// valid from StartsAt until EndsAt, either of which may be zero for no
+// limit, and can be redeemed MaxUses times, or any number of times when
+// zero.
+type Coupon struct {
+	Code     string    `json:"code"`
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Value    Decimal   `json:"value"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	MaxUses  int       `json:"max_uses"`
+}
+
+// Validate validates the coupon.
+func (c *Coupon) Validate() error {
+	if c.Code == "" {
+		return errors.New("coupon code is required")
+	}
+	if err := validateAdjustmentValue(c.Type, c.Value); err != nil {
+		return fmt.Errorf("invalid coupon %s, %v", c.Code, err)
+	}
+	if !c.StartsAt.IsZero() && !c.EndsAt.IsZero() && !c.EndsAt.After(c.StartsAt) {
+		return fmt.Errorf("invalid coupon %s, ends at must be after starts at", c.Code)
+	}
+	if c.MaxUses < 0 {
+		return fmt.Errorf("invalid coupon %s, max uses must not be negative", c.Code)
+	}
+
+	return nil
+}
+
+// validateAdjustmentValue validates the type and value of an adjustment.
+func validateAdjustmentValue(t string, v Decimal) error {
+	switch t {
+	case "percentage":
+		if v < 0 || v > DecimalFromInt(100) {
+			return errors.New("percentage must be between 0 and 100")
+		}
+	case "fixed":
+		if v < 0 {
+			return errors.New("fixed amount must not be negative")
+		}
+	default:
+		return errors.New("type must be percentage or fixed")
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"estimator/utils"
+)
+
+// ProductList defines the product list module, which lets the customer pick
+// quantities of catalog items.
+type ProductList struct {
+	ID         string                `json:"id"`
+	Type       string                `json:"type"`
+	Name       string                `json:"name"`
+	Properties ProductListProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (pl *ProductList) SetID(id string) {
+	pl.ID = id
+}
+
+// GetID implements the Module interface.
+func (pl *ProductList) GetID() string {
+	return pl.ID
+}
+
+// GetType implements the Module interface.
+func (pl *ProductList) GetType() string {
+	return pl.Type
+}
+
+// GetName implements the Module interface.
+func (pl *ProductList) GetName() string {
+	return pl.Name
+}
+
+// Validate implements the Module interface.
+func (pl *ProductList) Validate() error {
+	// Check type.
+	if err := ValidateType(pl.Type); err != nil {
+		return err
+	}
+
+	// Check items.
+	if len(pl.Properties.Items) == 0 {
+		return errors.New("property items must not be empty")
+	}
+	seen := map[string]bool{}
+	for _, v := range pl.Properties.Items {
+		if v == "" || seen[v] {
+			return errors.New("invalid property items, IDs must be unique and not empty")
+		}
+		seen[v] = true
+	}
+
+	return nil
+}
+
+// ValidateAnswer implements the AnswerValidator interface. The answer is
+// expected to be an object of whole number quantities keyed by catalog item
+// ID. Catalog item quantity limits are checked by the submission service.
+func (pl *ProductList) ValidateAnswer(answer interface{}) error {
+	// Get the answer as a map.
+	var m map[string]interface{}
+	if answer != nil {
+		var ok bool
+		if m, ok = answer.(map[string]interface{}); !ok {
+			return fmt.Errorf("%s must be an object of quantities keyed by item", pl.Name)
+		}
+	}
+
+	// Check the quantities.
+	selected := false
+	for id, v := range m {
+		if !utils.SliceContains(pl.Properties.Items, id) {
+			return fmt.Errorf("%s has an unknown item %s", pl.Name, id)
+		}
+		q, ok := v.(float64)
+		if !ok || q != float64(int(q)) || q < 0 {
+			return fmt.Errorf("%s[%s] must be a whole number", pl.Name, id)
+		}
+		if q > 0 {
+			selected = true
+		}
+	}
+
+	// Handle empty answers.
+	if !selected && pl.Properties.Required {
+		return fmt.Errorf("%s is required", pl.Name)
+	}
+
+	return nil
+}
+
+// Quantities gets the positive quantities from a validated answer, keyed by
+// catalog item ID.
+func (pl *ProductList) Quantities(answer interface{}) map[string]int {
+	quantities := map[string]int{}
+	m, _ := answer.(map[string]interface{})
+	for id, v := range m {
+		if q, _ := v.(float64); q > 0 {
+			quantities[id] = int(q)
+		}
+	}
+
+	return quantities
+}
+
+// ProductListProperties defines the product list module properties. Items
+// are catalog item IDs, so catalog price changes apply to every form.
+type ProductListProperties struct {
+	Label    string   `json:"label"`
+	Sublabel string   `json:"sublabel"`
+	Tooltip  string   `json:"tooltip"`
+	Required bool     `json:"required"`
+	Items    []string `json:"items"`
+}
@@ -0,0 +1,135 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"estimator/utils"
+)
+
+// Quote statuses.
+const (
+	QuoteDraft    = "draft"
+	QuoteSent     = "sent"
+	QuoteAccepted = "accepted"
+	QuoteDeclined = "declined"
+	QuoteExpired  = "expired"
+)
+
+// QuoteStatuses defines the available quote statuses.
+var QuoteStatuses []string = []string{
+	QuoteDraft,
+	QuoteSent,
+	QuoteAccepted,
+	QuoteDeclined,
+	QuoteExpired,
+}
+
+// quoteTransitions maps each quote status to the statuses it can move to.
+// Accepted, declined and expired quotes are final.
+var quoteTransitions = map[string][]string{
+	QuoteDraft: {QuoteSent, QuoteExpired},
+	QuoteSent:  {QuoteAccepted, QuoteDeclined, QuoteExpired},
+}
+
+// Quote event types. Every status change also produces an event with the
+// type "quote." followed by the new status, such as "quote.accepted".
+const (
+	QuoteEventCreated = "quote.created"
+)
+
+// QuoteSignatureMaxSize defines the maximum size of a quote acceptance
+// signature in bytes.
+const QuoteSignatureMaxSize = 256 << 10
+
+// DefaultQuoteValidity defines how long a quote is valid when no expiry
+// date is given.
+const DefaultQuoteValidity = 30 * 24 * time.Hour
+
+var (
+	// ErrInvalidTransition is returned when a quote can't move to the
+	// requested status.
+	ErrInvalidTransition = errors.New("invalid quote status transition")
+)
+
+// Quote defines a quote, an immutable snapshot of a form, its answers and
+// the estimate computed for them. Only the status of a quote changes after
+// it is created. Open quotes expire at ExpiresAt. Token is the unguessable
+// token of the public link customers view, accept or decline the quote
+// with, and Response records their answer. Customers must sign to accept
+// when RequireSignature is set.
+type Quote struct {
+	ID               string
+	Number           string
+	Token            string
+	Form             *Form
+	Answers          map[string]interface{}
+	Estimate         *Estimate
+	Status           string
+	ExpiresAt        time.Time
+	RequireSignature bool
+	Response         *QuoteResponse
+	Created          time.Time
+	Updated          time.Time
+}
+
+// QuoteResponse defines how a customer responded to a quote through its
+// public link. Signature is a signature module answer, if the customer
+// signed.
+type QuoteResponse struct {
+	Status    string
+	Name      string
+	Signature map[string]interface{}
+	IP        string
+	UserAgent string
+	At        time.Time
+}
+
+// QuoteEvent defines an event of a quote, such as it being accepted.
+type QuoteEvent struct {
+	Type  string
+	Quote *Quote
+	At    time.Time
+}
+
+// QuoteNumber returns the human readable number of the nth quote of the
+// given year, such as Q-2026-00042.
+func QuoteNumber(year, n int) string {
+	return fmt.Sprintf("Q-%d-%05d", year, n)
+}
+
+// ValidateQuoteStatus validates the given quote status.
+func ValidateQuoteStatus(status string) error {
+	if !utils.SliceContains(QuoteStatuses, status) {
+		return errors.New("invalid quote status")
+	}
+
+	return nil
+}
+
+// Open checks if the quote is still waiting to be accepted or declined.
+func (q *Quote) Open() bool {
+	return q.Status == QuoteDraft || q.Status == QuoteSent
+}
+
+// CanTransition checks if the quote can move to the given status.
+func (q *Quote) CanTransition(status string) bool {
+	return utils.SliceContains(quoteTransitions[q.Status], status)
+}
+
+// SignatureModule returns the signature module customers sign with to
+// accept the quote, which validates their signature.
+func (q *Quote) SignatureModule() *Signature {
+	return &Signature{
+		Type: "signature",
+		Name: "signature",
+		Properties: SignatureProperties{
+			Label:       "Signature",
+			Required:    q.RequireSignature,
+			RequireName: true,
+			Storage:     "inline",
+			MaxSize:     QuoteSignatureMaxSize,
+		},
+	}
+}
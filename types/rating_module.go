@@ -0,0 +1,133 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"estimator/utils"
+)
+
+// RatingIcons defines the available icons for a rating module.
+var RatingIcons []string = []string{
+	"star",
+	"heart",
+	"emoji",
+	"number",
+}
+
+// Rating defines the rating module.
+type Rating struct {
+	ID         string           `json:"id"`
+	Type       string           `json:"type"`
+	Name       string           `json:"name"`
+	Properties RatingProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (r *Rating) SetID(id string) {
+	r.ID = id
+}
+
+// GetID implements the Module interface.
+func (r *Rating) GetID() string {
+	return r.ID
+}
+
+// GetType implements the Module interface.
+func (r *Rating) GetType() string {
+	return r.Type
+}
+
+// GetName implements the Module interface.
+func (r *Rating) GetName() string {
+	return r.Name
+}
+
+// Validate implements the Module interface.
+func (r *Rating) Validate() error {
+	// Check type.
+	if err := ValidateType(r.Type); err != nil {
+		return err
+	}
+
+	// Check scale.
+	if r.Properties.Scale < 2 || r.Properties.Scale > 10 {
+		return errors.New("invalid property scale, must be between 2 and 10")
+	}
+
+	// Check icon.
+	if !utils.SliceContains(RatingIcons, r.Properties.Icon) {
+		return errors.New("invalid property icon")
+	}
+
+	// Check default.
+	if r.Properties.Default < 0 || r.Properties.Default > r.Properties.Scale {
+		return errors.New("invalid property default, must be between 0 and scale")
+	}
+
+	// Check price.
+	if err := ValidatePriceRange(r.Properties.Price, r.Properties.MinPrice, r.Properties.MaxPrice); err != nil {
+		return fmt.Errorf("invalid property price, %v", err)
+	}
+
+	return nil
+}
+
+// Prefill implements the Prefiller interface, overriding the default with
+// the prefill parameter when one is set and holds a rating on the scale.
+func (r *Rating) Prefill(params url.Values, referrer string) []string {
+	if r.Properties.PrefillParam == "" || !params.Has(r.Properties.PrefillParam) {
+		return nil
+	}
+
+	v, err := strconv.Atoi(params.Get(r.Properties.PrefillParam))
+	if err != nil || r.ValidateAnswer(float64(v)) != nil {
+		return nil
+	}
+	r.Properties.Default = v
+
+	return []string{r.Properties.PrefillParam}
+}
+
+// ValidateAnswer implements the AnswerValidator interface.
+func (r *Rating) ValidateAnswer(answer interface{}) error {
+	// Handle empty answers.
+	if answer == nil {
+		if r.Properties.Required {
+			return fmt.Errorf("%s is required", r.Name)
+		}
+		return nil
+	}
+
+	// Get the answer as a whole number.
+	v, ok := answer.(float64)
+	if !ok || v != float64(int(v)) {
+		return fmt.Errorf("%s must be a whole number", r.Name)
+	}
+
+	// Check range.
+	if v < 1 || int(v) > r.Properties.Scale {
+		return fmt.Errorf("%s must be between 1 and %d", r.Name, r.Properties.Scale)
+	}
+
+	return nil
+}
+
+// RatingProperties defines the rating module properties. When Price is set,
+// the estimate charges it per point of the answer, optionally ranging from
+// MinPrice to MaxPrice. Default is the starting rating, or 0 for none.
+type RatingProperties struct {
+	Label        string  `json:"label"`
+	Sublabel     string  `json:"sublabel"`
+	Tooltip      string  `json:"tooltip"`
+	Required     bool    `json:"required"`
+	Scale        int     `json:"scale"`
+	Icon         string  `json:"icon"`
+	Price        Decimal `json:"price"`
+	MinPrice     Decimal `json:"min_price"`
+	MaxPrice     Decimal `json:"max_price"`
+	Default      int     `json:"default"`
+	PrefillParam string  `json:"prefill_param"`
+}
@@ -0,0 +1,210 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"estimator/utils/formula"
+)
+
+// Repeater defines the repeater module, a group of child modules the
+// customer can answer any number of times, such as once for each window to
+// replace. The answer is an array with an object of child answers per item.
+type Repeater struct {
+	ID         string             `json:"id"`
+	Type       string             `json:"type"`
+	Name       string             `json:"name"`
+	Properties RepeaterProperties `json:"properties"`
+	Modules    []Module           `json:"modules"`
+}
+
+// SetID implements the Module interface.
+func (r *Repeater) SetID(id string) {
+	r.ID = id
+}
+
+// GetID implements the Module interface.
+func (r *Repeater) GetID() string {
+	return r.ID
+}
+
+// GetType implements the Module interface.
+func (r *Repeater) GetType() string {
+	return r.Type
+}
+
+// GetName implements the Module interface.
+func (r *Repeater) GetName() string {
+	return r.Name
+}
+
+// Validate implements the Module interface, validating the child modules
+// as well.
+func (r *Repeater) Validate() error {
+	// Check type.
+	if err := ValidateType(r.Type); err != nil {
+		return err
+	}
+
+	// Check item limits.
+	if r.Properties.MinItems < 0 {
+		return errors.New("property min items must not be negative")
+	}
+	if r.Properties.MaxItems != 0 && r.Properties.MaxItems < r.Properties.MinItems {
+		return errors.New("property max items must not be less than min items")
+	}
+
+	// Check item price.
+	if err := ValidatePriceRange(r.Properties.ItemPrice, r.Properties.ItemMinPrice, r.Properties.ItemMaxPrice); err != nil {
+		return fmt.Errorf("invalid property item price, %v", err)
+	}
+
+	// Check the child modules.
+	if len(r.Modules) == 0 {
+		return errors.New("repeater modules must not be empty")
+	}
+	for _, module := range r.Modules {
+		if err := module.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Modules that act on the whole submission can't be repeated, including
+	// those in sections of the repeater.
+	for _, module := range Flatten(r.Modules) {
+		switch module.(type) {
+		case *PageBreak, *Signature, *Consent, *Hidden:
+			return fmt.Errorf("%s modules are not allowed in a repeater", module.GetType())
+		}
+	}
+
+	// Check the quantity module.
+	names := map[string]bool{}
+	for _, module := range Flatten(r.Modules) {
+		names[module.GetName()] = true
+	}
+	if r.Properties.QuantityModule != "" && !names[r.Properties.QuantityModule] {
+		return errors.New("property quantity module must be the name of a child module")
+	}
+
+	// Check the quantity formula.
+	if r.Properties.QuantityFormula != "" {
+		if r.Properties.QuantityModule != "" {
+			return errors.New("only one of properties quantity module and quantity formula can be set")
+		}
+		f, err := formula.Parse(r.Properties.QuantityFormula)
+		if err != nil {
+			return fmt.Errorf("invalid property quantity formula, %v", err)
+		}
+		for _, v := range f.Names() {
+			if !names[v] {
+				return fmt.Errorf("invalid property quantity formula, %s is not the name of a child module", v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateAnswer implements the AnswerValidator interface. Only the number
+// of items is checked, the child answers of each item are validated by the
+// submission service.
+func (r *Repeater) ValidateAnswer(answer interface{}) error {
+	// Handle no answer.
+	if answer == nil {
+		if r.Properties.MinItems > 0 {
+			return fmt.Errorf("%s is required", r.Name)
+		}
+		return nil
+	}
+
+	// Get the items.
+	items, err := r.Items(answer)
+	if err != nil {
+		return err
+	}
+
+	// Check the number of items.
+	if len(items) < r.Properties.MinItems {
+		return fmt.Errorf("%s must have at least %d items", r.Name, r.Properties.MinItems)
+	}
+	if r.Properties.MaxItems != 0 && len(items) > r.Properties.MaxItems {
+		return fmt.Errorf("%s must have at most %d items", r.Name, r.Properties.MaxItems)
+	}
+
+	return nil
+}
+
+// Items returns the child answers of each item of the given answer.
+func (r *Repeater) Items(answer interface{}) ([]map[string]interface{}, error) {
+	items := []map[string]interface{}{}
+	if answer == nil {
+		return items, nil
+	}
+
+	s, ok := answer.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of items", r.Name)
+	}
+	for i, v := range s {
+		item, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be an object", r.Name, i)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// ItemQuantity returns the quantity the item price is charged for on the
+// given item, which is the answer to the quantity module or the result of
+// the quantity formula when either is set, or otherwise 1.
+func (r *Repeater) ItemQuantity(item map[string]interface{}) (float64, error) {
+	switch {
+	case r.Properties.QuantityFormula != "":
+		f, err := formula.Parse(r.Properties.QuantityFormula)
+		if err != nil {
+			return 0, err
+		}
+		q, err := f.Eval(item)
+		if err != nil {
+			return 0, err
+		}
+		if q < 0 {
+			return 0, errors.New("quantity must not be negative")
+		}
+		return q, nil
+	case r.Properties.QuantityModule != "":
+		q, _ := item[r.Properties.QuantityModule].(float64)
+		return q, nil
+	}
+
+	return 1, nil
+}
+
+// Children implements the Parent interface.
+func (r *Repeater) Children() []Module {
+	return r.Modules
+}
+
+// RepeaterProperties defines the repeater module properties. A MaxItems of
+// zero allows any number of items. ItemLabel is used to label each item,
+// such as "Window", and ItemPrice is charged per item, multiplied by the
+// answer to the QuantityModule child or the result of the QuantityFormula
+// over the item answers, such as "width * height / 144", when set.
+// ItemMinPrice and ItemMaxPrice optionally give the range of the item
+// price.
+type RepeaterProperties struct {
+	Label           string  `json:"label"`
+	Sublabel        string  `json:"sublabel"`
+	Tooltip         string  `json:"tooltip"`
+	MinItems        int     `json:"min_items"`
+	MaxItems        int     `json:"max_items"`
+	ItemLabel       string  `json:"item_label"`
+	ItemPrice       Decimal `json:"item_price"`
+	ItemMinPrice    Decimal `json:"item_min_price"`
+	ItemMaxPrice    Decimal `json:"item_max_price"`
+	QuantityModule  string  `json:"quantity_module"`
+	QuantityFormula string  `json:"quantity_formula"`
+}
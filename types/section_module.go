@@ -0,0 +1,82 @@
+package types
+
+import "errors"
+
+// Section defines the section module, a container that groups child
+// modules.
+type Section struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Properties SectionProperties `json:"properties"`
+	Modules    []Module          `json:"modules"`
+}
+
+// SetID implements the Module interface.
+func (s *Section) SetID(id string) {
+	s.ID = id
+}
+
+// GetID implements the Module interface.
+func (s *Section) GetID() string {
+	return s.ID
+}
+
+// GetType implements the Module interface.
+func (s *Section) GetType() string {
+	return s.Type
+}
+
+// GetName implements the Module interface.
+func (s *Section) GetName() string {
+	return s.Name
+}
+
+// Validate implements the Module interface, validating the child modules
+// as well.
+func (s *Section) Validate() error {
+	// Check type.
+	if err := ValidateType(s.Type); err != nil {
+		return err
+	}
+
+	// Check visibility condition.
+	if s.Properties.VisibleIf != nil {
+		if err := s.Properties.VisibleIf.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Check the child modules.
+	for _, module := range s.Modules {
+		if _, ok := module.(*PageBreak); ok {
+			return errors.New("page breaks are not allowed in a section")
+		}
+		if err := module.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Children implements the Container interface.
+func (s *Section) Children() []Module {
+	return s.Modules
+}
+
+// Visible implements the Container interface.
+func (s *Section) Visible(answers map[string]interface{}, locations Locations) bool {
+	return s.Properties.VisibleIf == nil || s.Properties.VisibleIf.Evaluate(answers, locations)
+}
+
+// SectionProperties defines the section module properties. The section and
+// its child modules are only shown, and validated, when VisibleIf is nil or
+// evaluates to true.
+type SectionProperties struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Collapsible bool       `json:"collapsible"`
+	Collapsed   bool       `json:"collapsed"`
+	VisibleIf   *Condition `json:"visible_if"`
+}
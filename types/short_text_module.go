@@ -1,5 +1,7 @@
 package types
 
+import "net/url"
+
 // ShortText defines the short text module.
 type ShortText struct {
 	ID         string              `json:"id"`
@@ -13,11 +15,21 @@ func (st *ShortText) SetID(id string) {
 	st.ID = id
 }
 
+// GetID implements the Module interface.
+func (st *ShortText) GetID() string {
+	return st.ID
+}
+
 // GetType implements the Module interface.
 func (st *ShortText) GetType() string {
 	return st.Type
 }
 
+// GetName implements the Module interface.
+func (st *ShortText) GetName() string {
+	return st.Name
+}
+
 // Validate implements the Module interface.
 func (st *ShortText) Validate() error {
 	// Check type.
@@ -28,16 +40,29 @@ func (st *ShortText) Validate() error {
 	return nil
 }
 
+// Prefill implements the Prefiller interface, overriding the default with
+// the prefill parameter when one is set and present.
+func (st *ShortText) Prefill(params url.Values, referrer string) []string {
+	if st.Properties.PrefillParam == "" || !params.Has(st.Properties.PrefillParam) {
+		return nil
+	}
+
+	st.Properties.Default = params.Get(st.Properties.PrefillParam)
+
+	return []string{st.Properties.PrefillParam}
+}
+
 // ShortTextProperties defines the short text module properties.
 type ShortTextProperties struct {
-	Label       string `json:"label"`
-	Sublabel    string `json:"sublabel"`
-	Tooltip     string `json:"tooltip"`
-	Required    bool   `json:"required"`
-	Placeholder string `json:"placeholder"`
-	Default     string `json:"default"`
-	Suffix      string `json:"suffix"`
-	WidthType   bool   `json:"width_type"`
-	Width       int    `json:"width"`
-	Validation  string `json:"validation"`
+	Label        string `json:"label"`
+	Sublabel     string `json:"sublabel"`
+	Tooltip      string `json:"tooltip"`
+	Required     bool   `json:"required"`
+	Placeholder  string `json:"placeholder"`
+	Default      string `json:"default"`
+	Suffix       string `json:"suffix"`
+	WidthType    bool   `json:"width_type"`
+	Width        int    `json:"width"`
+	Validation   string `json:"validation"`
+	PrefillParam string `json:"prefill_param"`
 }
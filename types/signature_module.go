@@ -0,0 +1,279 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+
+	"estimator/utils"
+)
+
+// pngDataURLPrefix defines the prefix of a base64 encoded PNG data URL.
+const pngDataURLPrefix = "data:image/png;base64,"
+
+// SignatureMaxDimension defines the maximum signature image width and
+// height in pixels.
+const SignatureMaxDimension = 2048
+
+// SignatureStorages defines where a signature module keeps signatures.
+var SignatureStorages []string = []string{
+	"inline",
+	"blob",
+}
+
+// Signature defines the signature module.
+type Signature struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	Name       string              `json:"name"`
+	Properties SignatureProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (s *Signature) SetID(id string) {
+	s.ID = id
+}
+
+// GetID implements the Module interface.
+func (s *Signature) GetID() string {
+	return s.ID
+}
+
+// GetType implements the Module interface.
+func (s *Signature) GetType() string {
+	return s.Type
+}
+
+// GetName implements the Module interface.
+func (s *Signature) GetName() string {
+	return s.Name
+}
+
+// Validate implements the Module interface.
+func (s *Signature) Validate() error {
+	// Check type.
+	if err := ValidateType(s.Type); err != nil {
+		return err
+	}
+
+	// Check storage.
+	if !utils.SliceContains(SignatureStorages, s.Properties.Storage) {
+		return errors.New("invalid property storage")
+	}
+
+	// Check max size.
+	if s.Properties.MaxSize <= 0 {
+		return errors.New("invalid property max size, must be greater than 0")
+	}
+
+	return nil
+}
+
+// ValidateAnswer implements the AnswerValidator interface. The answer is
+// expected to be an object with the signer "name" and either "strokes", an
+// array of strokes that are each an array of points with "x" and "y", or
+// "image", a PNG data URL.
+func (s *Signature) ValidateAnswer(answer interface{}) error {
+	// Handle empty answers.
+	if answer == nil {
+		if s.Properties.Required {
+			return fmt.Errorf("%s is required", s.Name)
+		}
+		return nil
+	}
+
+	// Get the answer as a map.
+	m, ok := answer.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s must be an object", s.Name)
+	}
+
+	// Check the signer name.
+	name, _ := m["name"].(string)
+	if s.Properties.RequireName && strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%s signer name is required", s.Name)
+	}
+
+	// Check the signature data.
+	strokes, hasStrokes := m["strokes"]
+	image, hasImage := m["image"]
+	switch {
+	case hasStrokes && hasImage:
+		return fmt.Errorf("%s must have either strokes or an image, not both", s.Name)
+	case hasStrokes:
+		return s.validateStrokes(strokes)
+	case hasImage:
+		imageStr, ok := image.(string)
+		if !ok {
+			return fmt.Errorf("%s image must be a PNG data URL", s.Name)
+		}
+		_, err := s.DecodeImage(imageStr)
+		return err
+	default:
+		return fmt.Errorf("%s must have strokes or an image", s.Name)
+	}
+}
+
+// validateStrokes validates signature stroke data.
+func (s *Signature) validateStrokes(strokes interface{}) error {
+	// Check size.
+	b, err := json.Marshal(strokes)
+	if err != nil {
+		return err
+	}
+	if len(b) > s.Properties.MaxSize {
+		return fmt.Errorf("%s is too large", s.Name)
+	}
+
+	// Check the strokes.
+	strokesSlice, ok := strokes.([]interface{})
+	if !ok {
+		return fmt.Errorf("%s strokes must be an array", s.Name)
+	}
+	drawn := false
+	for _, v := range strokesSlice {
+		points, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s stroke must be an array of points", s.Name)
+		}
+		for _, p := range points {
+			point, ok := p.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s point must be an object", s.Name)
+			}
+			_, xOK := point["x"].(float64)
+			_, yOK := point["y"].(float64)
+			if !xOK || !yOK {
+				return fmt.Errorf("%s point must have numeric x and y", s.Name)
+			}
+		}
+		if len(points) > 1 {
+			drawn = true
+		}
+	}
+	if !drawn {
+		return fmt.Errorf("%s is empty", s.Name)
+	}
+
+	return nil
+}
+
+// DecodeImage decodes and checks a PNG data URL signature, returning the
+// PNG data.
+func (s *Signature) DecodeImage(dataURL string) ([]byte, error) {
+	// Decode the data URL.
+	if !strings.HasPrefix(dataURL, pngDataURLPrefix) {
+		return nil, fmt.Errorf("%s image must be a PNG data URL", s.Name)
+	}
+	encoded := strings.TrimPrefix(dataURL, pngDataURLPrefix)
+	if base64.StdEncoding.DecodedLen(len(encoded)) > s.Properties.MaxSize+2 {
+		return nil, fmt.Errorf("%s is too large", s.Name)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s image is not valid base64", s.Name)
+	}
+	if len(data) > s.Properties.MaxSize {
+		return nil, fmt.Errorf("%s is too large", s.Name)
+	}
+
+	// Check the dimensions before decoding the pixels.
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s image is not a valid PNG", s.Name)
+	}
+	if cfg.Width < 1 || cfg.Height < 1 || cfg.Width > SignatureMaxDimension || cfg.Height > SignatureMaxDimension {
+		return nil, fmt.Errorf("%s image dimensions are not allowed", s.Name)
+	}
+
+	// Decode the image.
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s image is not a valid PNG", s.Name)
+	}
+
+	// Check something was drawn.
+	if !drawn(img) {
+		return nil, fmt.Errorf("%s is empty", s.Name)
+	}
+
+	return data, nil
+}
+
+// drawn checks if at least one pixel of the image is neither transparent
+// nor white. The pixels of the types the PNG decoder produces for 8-bit
+// images are read directly.
+func drawn(img image.Image) bool {
+	switch m := img.(type) {
+	case *image.NRGBA:
+		for i := 0; i+3 < len(m.Pix); i += 4 {
+			if m.Pix[i+3] != 0 && (m.Pix[i] != 0xff || m.Pix[i+1] != 0xff || m.Pix[i+2] != 0xff) {
+				return true
+			}
+		}
+		return false
+	case *image.RGBA:
+		// Premultiplied white has every channel equal to the alpha.
+		for i := 0; i+3 < len(m.Pix); i += 4 {
+			a := m.Pix[i+3]
+			if a != 0 && (m.Pix[i] != a || m.Pix[i+1] != a || m.Pix[i+2] != a) {
+				return true
+			}
+		}
+		return false
+	case *image.Gray:
+		for _, v := range m.Pix {
+			if v != 0xff {
+				return true
+			}
+		}
+		return false
+	case *image.Paletted:
+		// Check each palette color once.
+		colors := make([]bool, len(m.Palette))
+		for i, c := range m.Palette {
+			colors[i] = drawnColor(c.RGBA())
+		}
+		for _, v := range m.Pix {
+			if int(v) < len(colors) && colors[v] {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Handle the other types, such as 16-bit images.
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if drawnColor(img.At(x, y).RGBA()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// drawnColor checks if the premultiplied color is neither transparent nor
+// white.
+func drawnColor(r, g, b, a uint32) bool {
+	return a != 0 && (r != a || g != a || b != a)
+}
+
+// SignatureProperties defines the signature module properties. MaxSize
+// limits the PNG size, or the encoded stroke data size, in bytes.
+type SignatureProperties struct {
+	Label       string `json:"label"`
+	Sublabel    string `json:"sublabel"`
+	Tooltip     string `json:"tooltip"`
+	Required    bool   `json:"required"`
+	RequireName bool   `json:"require_name"`
+	Storage     string `json:"storage"`
+	MaxSize     int    `json:"max_size"`
+}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+)
+
+// Slider defines the slider module.
+type Slider struct {
+	ID         string           `json:"id"`
+	Type       string           `json:"type"`
+	Name       string           `json:"name"`
+	Properties SliderProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (s *Slider) SetID(id string) {
+	s.ID = id
+}
+
+// GetID implements the Module interface.
+func (s *Slider) GetID() string {
+	return s.ID
+}
+
+// GetType implements the Module interface.
+func (s *Slider) GetType() string {
+	return s.Type
+}
+
+// GetName implements the Module interface.
+func (s *Slider) GetName() string {
+	return s.Name
+}
+
+// Validate implements the Module interface.
+func (s *Slider) Validate() error {
+	// Check type.
+	if err := ValidateType(s.Type); err != nil {
+		return err
+	}
+
+	// Check range and step.
+	if s.Properties.Max <= s.Properties.Min {
+		return errors.New("property max must be greater than min")
+	}
+	if s.Properties.Step <= 0 {
+		return errors.New("invalid property step, must be greater than 0")
+	}
+
+	// Check ticks.
+	for _, v := range s.Properties.Ticks {
+		if v.Value < s.Properties.Min || v.Value > s.Properties.Max {
+			return errors.New("invalid tick, value must be between min and max")
+		}
+	}
+
+	// Check default.
+	if s.Properties.Default != nil && (*s.Properties.Default < s.Properties.Min || *s.Properties.Default > s.Properties.Max) {
+		return errors.New("invalid property default, must be between min and max")
+	}
+
+	// Check price.
+	if err := ValidatePriceRange(s.Properties.Price, s.Properties.MinPrice, s.Properties.MaxPrice); err != nil {
+		return fmt.Errorf("invalid property price, %v", err)
+	}
+
+	return nil
+}
+
+// Prefill implements the Prefiller interface, overriding the default with
+// the prefill parameter when one is set and holds a number the slider can
+// be set to.
+func (s *Slider) Prefill(params url.Values, referrer string) []string {
+	if s.Properties.PrefillParam == "" || !params.Has(s.Properties.PrefillParam) {
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(params.Get(s.Properties.PrefillParam), 64)
+	if err != nil || s.ValidateAnswer(v) != nil {
+		return nil
+	}
+	s.Properties.Default = &v
+
+	return []string{s.Properties.PrefillParam}
+}
+
+// ValidateAnswer implements the AnswerValidator interface.
+func (s *Slider) ValidateAnswer(answer interface{}) error {
+	// Handle empty answers.
+	if answer == nil {
+		if s.Properties.Required {
+			return fmt.Errorf("%s is required", s.Name)
+		}
+		return nil
+	}
+
+	// Get the answer as a number.
+	v, ok := answer.(float64)
+	if !ok {
+		return fmt.Errorf("%s must be a number", s.Name)
+	}
+
+	// Check range.
+	if v < s.Properties.Min || v > s.Properties.Max {
+		return fmt.Errorf("%s must be between %g and %g", s.Name, s.Properties.Min, s.Properties.Max)
+	}
+
+	// Check step, allowing for floating point error.
+	steps := (v - s.Properties.Min) / s.Properties.Step
+	if math.Abs(steps-math.Round(steps)) > 1e-9 {
+		return fmt.Errorf("%s must be in increments of %g", s.Name, s.Properties.Step)
+	}
+
+	return nil
+}
+
+// SliderProperties defines the slider module properties. When Price is set,
+// the estimate charges it per unit of the answer, optionally ranging from
+// MinPrice to MaxPrice. Default is the starting value, or nil to start at
+// Min.
+type SliderProperties struct {
+	Label        string       `json:"label"`
+	Sublabel     string       `json:"sublabel"`
+	Tooltip      string       `json:"tooltip"`
+	Required     bool         `json:"required"`
+	Min          float64      `json:"min"`
+	Max          float64      `json:"max"`
+	Step         float64      `json:"step"`
+	Ticks        []SliderTick `json:"ticks"`
+	ShowValue    bool         `json:"show_value"`
+	Price        Decimal      `json:"price"`
+	MinPrice     Decimal      `json:"min_price"`
+	MaxPrice     Decimal      `json:"max_price"`
+	Default      *float64     `json:"default"`
+	PrefillParam string       `json:"prefill_param"`
+}
+
+// SliderTick defines a labeled slider tick.
+type SliderTick struct {
+	Value float64 `json:"value"`
+	Label string  `json:"label"`
+}
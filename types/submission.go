@@ -0,0 +1,19 @@
+package types
+
+import "time"
+
+// Submission defines a form submission. Answers are keyed by module name.
+// Prefill holds the allow-listed query parameters the form was prefilled
+// with, and Coupon the code of the coupon redeemed, if any. FormRevision is
+// the number of the form revision the submission was made against.
+type Submission struct {
+	ID           string
+	FormID       string
+	FormRevision int
+	Answers      map[string]interface{}
+	Prefill      map[string]string
+	Referrer     string
+	IP           string
+	Coupon       string
+	Created      time.Time
+}
@@ -0,0 +1,172 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"estimator/utils"
+)
+
+// TimeLayout defines the layout times are submitted and stored in.
+const TimeLayout = "15:04"
+
+// TimeFormats defines the available display formats for a time module.
+var TimeFormats []string = []string{
+	"12h",
+	"24h",
+}
+
+// Time defines the time module.
+type Time struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Name       string         `json:"name"`
+	Properties TimeProperties `json:"properties"`
+}
+
+// SetID implements the Module interface.
+func (t *Time) SetID(id string) {
+	t.ID = id
+}
+
+// GetID implements the Module interface.
+func (t *Time) GetID() string {
+	return t.ID
+}
+
+// GetType implements the Module interface.
+func (t *Time) GetType() string {
+	return t.Type
+}
+
+// GetName implements the Module interface.
+func (t *Time) GetName() string {
+	return t.Name
+}
+
+// Validate implements the Module interface.
+func (t *Time) Validate() error {
+	// Check type.
+	if err := ValidateType(t.Type); err != nil {
+		return err
+	}
+
+	// Check format.
+	if !utils.SliceContains(TimeFormats, t.Properties.Format) {
+		return errors.New("invalid property format")
+	}
+
+	// Check time zone.
+	if _, err := LoadLocation(t.Properties.TimeZone); err != nil {
+		return err
+	}
+
+	// Check minimum and maximum times.
+	var min, max time.Time
+	var err error
+	if t.Properties.MinTime != "" {
+		if min, err = time.Parse(TimeLayout, t.Properties.MinTime); err != nil {
+			return errors.New("invalid property min time, must be in the format HH:MM")
+		}
+	}
+	if t.Properties.MaxTime != "" {
+		if max, err = time.Parse(TimeLayout, t.Properties.MaxTime); err != nil {
+			return errors.New("invalid property max time, must be in the format HH:MM")
+		}
+	}
+	if t.Properties.MinTime != "" && t.Properties.MaxTime != "" && min.After(max) {
+		return errors.New("property min time must not be after max time")
+	}
+
+	// Check step.
+	if t.Properties.Step < 0 || t.Properties.Step > 24*60 {
+		return errors.New("invalid property step, must be between 0 and 1440 minutes")
+	}
+
+	return nil
+}
+
+// ValidateAnswer implements the AnswerValidator interface.
+func (t *Time) ValidateAnswer(answer interface{}) error {
+	// Handle empty answers.
+	if answer == nil || answer == "" {
+		if t.Properties.Required {
+			return fmt.Errorf("%s is required", t.Name)
+		}
+		return nil
+	}
+
+	// Get the answer as a string.
+	s, ok := answer.(string)
+	if !ok {
+		return fmt.Errorf("%s must be a time string", t.Name)
+	}
+
+	// Parse the time.
+	v, err := time.Parse(TimeLayout, s)
+	if err != nil {
+		return fmt.Errorf("%s must be in the format HH:MM", t.Name)
+	}
+
+	// Check minimum time.
+	if t.Properties.MinTime != "" {
+		min, err := time.Parse(TimeLayout, t.Properties.MinTime)
+		if err != nil {
+			return errors.New("invalid property min time, must be in the format HH:MM")
+		}
+		if v.Before(min) {
+			return fmt.Errorf("%s must not be before %s", t.Name, t.Properties.MinTime)
+		}
+	}
+
+	// Check maximum time.
+	if t.Properties.MaxTime != "" {
+		max, err := time.Parse(TimeLayout, t.Properties.MaxTime)
+		if err != nil {
+			return errors.New("invalid property max time, must be in the format HH:MM")
+		}
+		if v.After(max) {
+			return fmt.Errorf("%s must not be after %s", t.Name, t.Properties.MaxTime)
+		}
+	}
+
+	// Check step, counted in minutes from midnight.
+	if t.Properties.Step > 0 && (v.Hour()*60+v.Minute())%t.Properties.Step != 0 {
+		return fmt.Errorf("%s must be in %d minute increments", t.Name, t.Properties.Step)
+	}
+
+	return nil
+}
+
+// Prefill implements the Prefiller interface, overriding the default with
+// the prefill parameter when one is set and holds a selectable time.
+func (t *Time) Prefill(params url.Values, referrer string) []string {
+	if t.Properties.PrefillParam == "" || !params.Has(t.Properties.PrefillParam) {
+		return nil
+	}
+
+	v := params.Get(t.Properties.PrefillParam)
+	if v == "" || t.ValidateAnswer(v) != nil {
+		return nil
+	}
+	t.Properties.Default = v
+
+	return []string{t.Properties.PrefillParam}
+}
+
+// TimeProperties defines the time module properties.
+type TimeProperties struct {
+	Label        string `json:"label"`
+	Sublabel     string `json:"sublabel"`
+	Tooltip      string `json:"tooltip"`
+	Required     bool   `json:"required"`
+	MinTime      string `json:"min_time"`
+	MaxTime      string `json:"max_time"`
+	Step         int    `json:"step"`
+	Format       string `json:"format"`
+	TimeZone     string `json:"time_zone"`
+	Default      string `json:"default"`
+	PrefillParam string `json:"prefill_param"`
+}
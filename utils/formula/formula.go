@@ -0,0 +1,409 @@
+// Package formula parses and evaluates arithmetic formulas over form
+// answers, such as "ceil(width * height / 144)". Formulas support numbers,
+// the + - * / operators, parentheses, the min, max, round, ceil and floor
+// functions, and answers referenced by module name. Names made of anything
+// other than letters, digits and underscores are written in braces, such as
+// {roof-area}.
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Formula defines a parsed formula.
+type Formula struct {
+	src   string
+	root  node
+	names []string
+}
+
+// Parse parses the given formula.
+func Parse(s string) (*Formula, error) {
+	p := &parser{src: s}
+	p.next()
+
+	// Parse the expression, which must take up the whole formula.
+	root, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", p.tok, p.tok.pos)
+	}
+
+	// Get the referenced names.
+	f := &Formula{src: s, root: root}
+	seen := map[string]bool{}
+	root.walk(func(n node) {
+		if v, ok := n.(variable); ok && !seen[string(v)] {
+			seen[string(v)] = true
+			f.names = append(f.names, string(v))
+		}
+	})
+
+	return f, nil
+}
+
+// String returns the formula as it was given.
+func (f *Formula) String() string {
+	return f.src
+}
+
+// Names returns the names referenced by the formula, in order of first use.
+func (f *Formula) Names() []string {
+	return f.names
+}
+
+// Eval evaluates the formula with the given answers. Numeric answers are
+// used as is, true is 1, and missing or other answers are 0.
+func (f *Formula) Eval(answers map[string]interface{}) (float64, error) {
+	v, err := f.root.eval(answers)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errors.New("formula result is not a number")
+	}
+
+	return v, nil
+}
+
+// node defines a node of a parsed formula.
+type node interface {
+	eval(answers map[string]interface{}) (float64, error)
+	walk(fn func(node))
+}
+
+// number defines a number node.
+type number float64
+
+func (n number) eval(answers map[string]interface{}) (float64, error) {
+	return float64(n), nil
+}
+
+func (n number) walk(fn func(node)) {
+	fn(n)
+}
+
+// variable defines an answer node.
+type variable string
+
+func (v variable) eval(answers map[string]interface{}) (float64, error) {
+	switch a := answers[string(v)].(type) {
+	case float64:
+		return a, nil
+	case bool:
+		if a {
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (v variable) walk(fn func(node)) {
+	fn(v)
+}
+
+// negate defines a unary minus node.
+type negate struct {
+	x node
+}
+
+func (n negate) eval(answers map[string]interface{}) (float64, error) {
+	x, err := n.x.eval(answers)
+	return -x, err
+}
+
+func (n negate) walk(fn func(node)) {
+	fn(n)
+	n.x.walk(fn)
+}
+
+// binary defines a binary operator node.
+type binary struct {
+	op   byte
+	x, y node
+}
+
+func (n binary) eval(answers map[string]interface{}) (float64, error) {
+	x, err := n.x.eval(answers)
+	if err != nil {
+		return 0, err
+	}
+	y, err := n.y.eval(answers)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case '+':
+		return x + y, nil
+	case '-':
+		return x - y, nil
+	case '*':
+		return x * y, nil
+	}
+	if y == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return x / y, nil
+}
+
+func (n binary) walk(fn func(node)) {
+	fn(n)
+	n.x.walk(fn)
+	n.y.walk(fn)
+}
+
+// call defines a function call node.
+type call struct {
+	fn   string
+	args []node
+}
+
+func (n call) eval(answers map[string]interface{}) (float64, error) {
+	args := make([]float64, len(n.args))
+	for i, v := range n.args {
+		x, err := v.eval(answers)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = x
+	}
+
+	switch n.fn {
+	case "min":
+		v := args[0]
+		for _, x := range args[1:] {
+			v = math.Min(v, x)
+		}
+		return v, nil
+	case "max":
+		v := args[0]
+		for _, x := range args[1:] {
+			v = math.Max(v, x)
+		}
+		return v, nil
+	case "round":
+		return math.Round(args[0]), nil
+	case "ceil":
+		return math.Ceil(args[0]), nil
+	}
+	return math.Floor(args[0]), nil
+}
+
+func (n call) walk(fn func(node)) {
+	fn(n)
+	for _, v := range n.args {
+		v.walk(fn)
+	}
+}
+
+// functions defines the available functions and their number of arguments,
+// where -1 is one or more.
+var functions = map[string]int{
+	"min":   -1,
+	"max":   -1,
+	"round": 1,
+	"ceil":  1,
+	"floor": 1,
+}
+
+// Token kinds.
+const (
+	tokEOF = iota
+	tokNumber
+	tokName
+	tokOp
+	tokInvalid
+)
+
+// token defines a token of a formula.
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of formula"
+	}
+	return strconv.Quote(t.text)
+}
+
+// parser defines a recursive descent formula parser.
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+// next reads the next token.
+func (p *parser) next() {
+	// Skip whitespace.
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		p.tok = token{kind: tokNumber, text: p.src[start:p.pos], pos: start}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || unicode.IsLetter(rune(p.src[p.pos])) || unicode.IsDigit(rune(p.src[p.pos]))) {
+			p.pos++
+		}
+		p.tok = token{kind: tokName, text: p.src[start:p.pos], pos: start}
+	case c == '{':
+		end := strings.IndexByte(p.src[p.pos:], '}')
+		if end < 0 {
+			p.tok = token{kind: tokInvalid, text: p.src[start:], pos: start}
+			p.pos = len(p.src)
+			return
+		}
+		p.pos += end + 1
+		p.tok = token{kind: tokName, text: p.src[start+1 : p.pos-1], pos: start}
+	case strings.IndexByte("+-*/(),", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+	default:
+		p.pos++
+		p.tok = token{kind: tokInvalid, text: string(c), pos: start}
+	}
+}
+
+// is checks if the current token is the given operator.
+func (p *parser) is(op string) bool {
+	return p.tok.kind == tokOp && p.tok.text == op
+}
+
+// expr parses a sum of terms.
+func (p *parser) expr() (node, error) {
+	x, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.is("+") || p.is("-") {
+		op := p.tok.text[0]
+		p.next()
+		y, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op: op, x: x, y: y}
+	}
+
+	return x, nil
+}
+
+// term parses a product of factors.
+func (p *parser) term() (node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.is("*") || p.is("/") {
+		op := p.tok.text[0]
+		p.next()
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op: op, x: x, y: y}
+	}
+
+	return x, nil
+}
+
+// unary parses a factor with optional leading minus signs.
+func (p *parser) unary() (node, error) {
+	if p.is("-") {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return negate{x: x}, nil
+	}
+
+	return p.primary()
+}
+
+// primary parses a number, name, function call or parenthesized
+// expression.
+func (p *parser) primary() (node, error) {
+	t := p.tok
+	switch {
+	case t.kind == tokNumber:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at position %d", t, t.pos)
+		}
+		p.next()
+		return number(v), nil
+	case t.kind == tokName:
+		p.next()
+
+		// Handle answers.
+		if !p.is("(") || p.src[t.pos] == '{' {
+			if t.text == "" {
+				return nil, fmt.Errorf("empty name at position %d", t.pos)
+			}
+			return variable(t.text), nil
+		}
+
+		// Handle function calls.
+		arity, ok := functions[t.text]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %s at position %d", t, t.pos)
+		}
+		p.next()
+		args := []node{}
+		for !p.is(")") {
+			if len(args) > 0 {
+				if !p.is(",") {
+					return nil, fmt.Errorf("expected \",\" or \")\" at position %d", p.tok.pos)
+				}
+				p.next()
+			}
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+		p.next()
+		if len(args) == 0 || (arity > 0 && len(args) != arity) {
+			return nil, fmt.Errorf("wrong number of arguments to %s at position %d", t.text, t.pos)
+		}
+		return call{fn: t.text, args: args}, nil
+	case p.is("("):
+		p.next()
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.is(")") {
+			return nil, fmt.Errorf("expected \")\" at position %d", p.tok.pos)
+		}
+		p.next()
+		return x, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+}
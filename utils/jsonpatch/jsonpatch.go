@@ -0,0 +1,402 @@
+// Package jsonpatch applies JSON Patch (RFC 6902) and JSON Merge Patch
+// (RFC 7396) documents to JSON values decoded into interface{}, such as
+// map[string]interface{} and []interface{}.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrTestFailed is returned when a test operation of a patch fails.
+	ErrTestFailed = errors.New("patch test operation failed")
+)
+
+// Operation defines a JSON Patch operation. From is used by move and copy
+// operations, and Value by add, replace and test operations.
+type Operation struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// Apply applies the given JSON Patch document to the given value, returning
+// the patched value. The operations are applied in order, and the patch is
+// applied completely or not at all, as the given value is never modified.
+func Apply(doc interface{}, patch []byte) (interface{}, error) {
+	// Decode the patch.
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, errors.New("patch must be an array of operations")
+	}
+
+	// Copy the value, so it is left as is if the patch fails.
+	doc, err := deepCopy(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the operations.
+	for i, op := range ops {
+		doc, err = apply(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// apply applies a single operation to the given value.
+func apply(doc interface{}, op Operation) (interface{}, error) {
+	// Get the path.
+	if op.Path == nil {
+		return nil, errors.New("path is required")
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the value.
+	var value interface{}
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("value is required for %s", op.Op)
+		}
+		if err := decode(*op.Value, &value); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get the from path.
+	var from []string
+	switch op.Op {
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("from is required for %s", op.Op)
+		}
+		from, err = parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle the operation.
+	switch op.Op {
+	case "add":
+		return add(doc, path, value)
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case "replace":
+		if _, err := get(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		doc, _, err = remove(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "move":
+		if len(path) > len(from) && *op.Path != *op.From && strings.HasPrefix(*op.Path, *op.From+"/") {
+			return nil, errors.New("can't move a value into one of its children")
+		}
+		doc, v, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	case "copy":
+		v, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		v, err = deepCopy(v)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	case "test":
+		v, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(v, value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("invalid operation %q", op.Op)
+	}
+}
+
+// Merge applies the given JSON Merge Patch document to the given value,
+// returning the patched value. Objects are merged recursively, null removes
+// a member and any other value, including arrays, replaces the target. The
+// given value is never modified.
+func Merge(doc interface{}, patch []byte) (interface{}, error) {
+	// Decode the patch.
+	var p interface{}
+	if err := decode(patch, &p); err != nil {
+		return nil, errors.New("patch must be valid JSON")
+	}
+
+	// Copy the value.
+	doc, err := deepCopy(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return merge(doc, p), nil
+}
+
+// merge merges the patch into the target.
+func merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = merge(t[k], v)
+	}
+
+	return t
+}
+
+// parsePointer parses a JSON Pointer (RFC 6901) into its reference tokens.
+func parsePointer(s string) ([]string, error) {
+	if s == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("invalid path %q", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, v := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(v, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// parseIndex parses an array index token. The index may be one past the
+// end of the array when end is set, where "-" also refers to.
+func parseIndex(token string, length int, end bool) (int, error) {
+	if end && token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > length || (i == length && !end) {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+
+	return i, nil
+}
+
+// get gets the value at the given path.
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch c := doc.(type) {
+		case map[string]interface{}:
+			v, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q could not be found", token)
+			}
+			doc = v
+		case []interface{}:
+			i, err := parseIndex(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = c[i]
+		default:
+			return nil, fmt.Errorf("path token %q does not refer to an object or array", token)
+		}
+	}
+
+	return doc, nil
+}
+
+// add adds the value at the given path, returning the updated value.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, rest := path[0], path[1:]
+
+	switch c := doc.(type) {
+	case map[string]interface{}:
+		if len(rest) == 0 {
+			c[token] = value
+			return c, nil
+		}
+		child, ok := c[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q could not be found", token)
+		}
+		v, err := add(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		c[token] = v
+		return c, nil
+	case []interface{}:
+		i, err := parseIndex(token, len(c), len(rest) == 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 {
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		}
+		v, err := add(c[i], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		c[i] = v
+		return c, nil
+	default:
+		return nil, fmt.Errorf("path token %q does not refer to an object or array", token)
+	}
+}
+
+// remove removes the value at the given path, returning the updated value
+// and the removed value.
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("can't remove the whole document")
+	}
+	token, rest := path[0], path[1:]
+
+	switch c := doc.(type) {
+	case map[string]interface{}:
+		child, ok := c[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("member %q could not be found", token)
+		}
+		if len(rest) == 0 {
+			delete(c, token)
+			return c, child, nil
+		}
+		v, removed, err := remove(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		c[token] = v
+		return c, removed, nil
+	case []interface{}:
+		i, err := parseIndex(token, len(c), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := c[i]
+			return append(c[:i], c[i+1:]...), removed, nil
+		}
+		v, removed, err := remove(c[i], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		c[i] = v
+		return c, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("path token %q does not refer to an object or array", token)
+	}
+}
+
+// decode decodes the given JSON into v, keeping numbers as json.Number so
+// they are not rounded to floats.
+func decode(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+
+	return nil
+}
+
+// equal reports whether the given values are equal, comparing numbers by
+// value, so 1 equals 1.0.
+func equal(a, b interface{}) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		ar, aOK := new(big.Rat).SetString(av.String())
+		br, bOK := new(big.Rat).SetString(bv.String())
+		return aOK && bOK && ar.Cmp(br) == 0
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			w, ok := bv[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return a == b
+}
+
+// deepCopy returns a deep copy of the given value.
+func deepCopy(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var c interface{}
+	if err := decode(b, &c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}